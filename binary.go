@@ -0,0 +1,217 @@
+package bn256
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*G1)(nil)
+	_ encoding.BinaryUnmarshaler = (*G1)(nil)
+	_ encoding.TextMarshaler     = (*G1)(nil)
+	_ encoding.TextUnmarshaler   = (*G1)(nil)
+	_ encoding.BinaryMarshaler   = (*G2)(nil)
+	_ encoding.BinaryUnmarshaler = (*G2)(nil)
+	_ encoding.TextMarshaler     = (*G2)(nil)
+	_ encoding.TextUnmarshaler   = (*G2)(nil)
+	_ encoding.BinaryMarshaler   = (*GT)(nil)
+	_ encoding.BinaryUnmarshaler = (*GT)(nil)
+	_ encoding.TextMarshaler     = (*GT)(nil)
+	_ encoding.TextUnmarshaler   = (*GT)(nil)
+	_ json.Marshaler             = (*G1)(nil)
+	_ json.Unmarshaler           = (*G1)(nil)
+	_ json.Marshaler             = (*G2)(nil)
+	_ json.Unmarshaler           = (*G2)(nil)
+	_ json.Marshaler             = (*GT)(nil)
+	_ json.Unmarshaler           = (*GT)(nil)
+)
+
+// unmarshalAll runs unmarshal on data and returns an error if it doesn't
+// consume all of it.
+func unmarshalAll(unmarshal func([]byte) ([]byte, error), data []byte) error {
+	rest, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("bn256: trailing data")
+	}
+	return nil
+}
+
+// unmarshalText decodes the base64 in text and passes it to unmarshal.
+func unmarshalText(unmarshal func([]byte) error, text []byte) error {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(data, text)
+	if err != nil {
+		return errors.New("bn256: malformed base64")
+	}
+	return unmarshal(data[:n])
+}
+
+// marshalJSON returns the output of Marshal in m as a JSON string holding
+// its standard base64.
+func marshalJSON(m []byte) ([]byte, error) {
+	return json.Marshal(base64.StdEncoding.EncodeToString(m))
+}
+
+// unmarshalJSON decodes the JSON string in data, which must hold the standard
+// base64 of an encoding whose length is one of sizes, and passes the encoding
+// to unmarshal. A JSON null leaves the value unchanged, as encoding/json
+// does for other types.
+func unmarshalJSON(unmarshal func([]byte) error, data []byte, name string, sizes ...int) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errors.New("bn256: " + name + " JSON value is not a string")
+	}
+	m, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return errors.New("bn256: " + name + " JSON value is not valid base64")
+	}
+	for _, size := range sizes {
+		if len(m) == size {
+			return unmarshal(m)
+		}
+	}
+	return errors.New("bn256: " + name + " JSON value has the wrong length")
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the output of
+// Marshal.
+func (e *G1) MarshalBinary() ([]byte, error) {
+	return e.Marshal(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It accepts the same
+// encodings as Unmarshal, and returns an error if data is longer than one
+// encoded point.
+func (e *G1) UnmarshalBinary(data []byte) error {
+	return unmarshalAll(e.Unmarshal, data)
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns the output of
+// Marshal in standard base64, so that points can be stored in JSON and other
+// text formats.
+func (e *G1) MarshalText() ([]byte, error) {
+	m := e.Marshal()
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(m)))
+	base64.StdEncoding.Encode(text, m)
+	return text, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It decodes the output of
+// MarshalText with UnmarshalBinary.
+func (e *G1) UnmarshalText(text []byte) error {
+	return unmarshalText(e.UnmarshalBinary, text)
+}
+
+// MarshalJSON implements json.Marshaler. It returns a JSON string with the
+// output of Marshal in standard base64, the same as MarshalText. The point at
+// infinity is encoded as all zeros.
+func (e *G1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(e.Marshal())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It decodes the output of
+// MarshalJSON, and returns an error if the string is not valid base64 or
+// doesn't decode to an encoding of the right length that UnmarshalBinary
+// accepts.
+func (e *G1) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e.UnmarshalBinary, data, "G1", g1Size)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the output of
+// Marshal.
+func (e *G2) MarshalBinary() ([]byte, error) {
+	return e.Marshal(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It accepts the same
+// encodings as Unmarshal, and returns an error if data is longer than one
+// encoded point. Like Unmarshal, it doesn't check that the point is in G₂;
+// see UnmarshalStrict.
+func (e *G2) UnmarshalBinary(data []byte) error {
+	return unmarshalAll(e.Unmarshal, data)
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns the output of
+// Marshal in standard base64, so that points can be stored in JSON and other
+// text formats.
+func (e *G2) MarshalText() ([]byte, error) {
+	m := e.Marshal()
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(m)))
+	base64.StdEncoding.Encode(text, m)
+	return text, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It decodes the output of
+// MarshalText with UnmarshalBinary.
+func (e *G2) UnmarshalText(text []byte) error {
+	return unmarshalText(e.UnmarshalBinary, text)
+}
+
+// MarshalJSON implements json.Marshaler. It returns a JSON string with the
+// output of Marshal in standard base64, the same as MarshalText. The point at
+// infinity is encoded as the single byte zero.
+func (e *G2) MarshalJSON() ([]byte, error) {
+	return marshalJSON(e.Marshal())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It decodes the output of
+// MarshalJSON, and returns an error if the string is not valid base64 or
+// doesn't decode to an encoding of the right length that UnmarshalBinary
+// accepts.
+func (e *G2) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e.UnmarshalBinary, data, "G2", g2Size, g2InfinitySize)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the output of
+// Marshal.
+func (e *GT) MarshalBinary() ([]byte, error) {
+	return e.Marshal(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It accepts the same
+// encodings as Unmarshal, and returns an error if data is longer than one
+// encoded element. Like Unmarshal, it doesn't check that the element is in
+// GT; see UnmarshalStrict.
+func (e *GT) UnmarshalBinary(data []byte) error {
+	return unmarshalAll(e.Unmarshal, data)
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns the output of
+// Marshal in standard base64, so that elements can be stored in JSON and
+// other text formats.
+func (e *GT) MarshalText() ([]byte, error) {
+	m := e.Marshal()
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(m)))
+	base64.StdEncoding.Encode(text, m)
+	return text, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It decodes the output of
+// MarshalText with UnmarshalBinary.
+func (e *GT) UnmarshalText(text []byte) error {
+	return unmarshalText(e.UnmarshalBinary, text)
+}
+
+// MarshalJSON implements json.Marshaler. It returns a JSON string with the
+// output of Marshal in standard base64, the same as MarshalText. The identity
+// element has no special encoding.
+func (e *GT) MarshalJSON() ([]byte, error) {
+	return marshalJSON(e.Marshal())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It decodes the output of
+// MarshalJSON, and returns an error if the string is not valid base64 or
+// doesn't decode to an encoding of the right length that UnmarshalBinary
+// accepts.
+func (e *GT) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e.UnmarshalBinary, data, "GT", gtSize)
+}
@@ -0,0 +1,148 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+// binaryTestValues holds a value of each group, as gob and json would see it
+// in a struct.
+type binaryTestValues struct {
+	A *G1
+	B *G2
+	C *GT
+}
+
+func randomBinaryTestValues(t *testing.T) *binaryTestValues {
+	_, a, err := RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, b, err := RandomG2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, c, err := RandomGT(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &binaryTestValues{a, b, c}
+}
+
+func (v *binaryTestValues) check(t *testing.T, got *binaryTestValues) {
+	t.Helper()
+	if !got.A.Equal(v.A) || !got.B.Equal(v.B) || !got.C.Equal(v.C) {
+		t.Fatal("decoded values don't match")
+	}
+}
+
+func TestGob(t *testing.T) {
+	want := randomBinaryTestValues(t)
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	got := new(binaryTestValues)
+	if err := gob.NewDecoder(buf).Decode(got); err != nil {
+		t.Fatal(err)
+	}
+	want.check(t, got)
+}
+
+func TestJSON(t *testing.T) {
+	want := randomBinaryTestValues(t)
+	want.B.p.SetInfinity()
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(binaryTestValues)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	want.check(t, got)
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	v := randomBinaryTestValues(t)
+	m1, m2, mt := v.A.Marshal(), v.B.Marshal(), v.C.Marshal()
+
+	tests := map[string]func() error{
+		"G1 empty":    func() error { return new(G1).UnmarshalBinary(nil) },
+		"G1 short":    func() error { return new(G1).UnmarshalBinary(m1[:len(m1)-1]) },
+		"G1 trailing": func() error { return new(G1).UnmarshalBinary(append(m1, 0)) },
+		"G2 empty":    func() error { return new(G2).UnmarshalBinary(nil) },
+		"G2 short":    func() error { return new(G2).UnmarshalBinary(m2[:len(m2)-1]) },
+		"G2 trailing": func() error { return new(G2).UnmarshalBinary(append(m2, 0)) },
+		"GT empty":    func() error { return new(GT).UnmarshalBinary(nil) },
+		"GT short":    func() error { return new(GT).UnmarshalBinary(mt[:len(mt)-1]) },
+		"GT trailing": func() error { return new(GT).UnmarshalBinary(append(mt, 0)) },
+		"bad base64":  func() error { return new(G1).UnmarshalText([]byte("not base64!")) },
+	}
+	for name, f := range tests {
+		if f() == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	want := randomBinaryTestValues(t)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(binaryTestValues)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	want.check(t, got)
+
+	// The identity elements have defined encodings too.
+	zero := new(big.Int)
+	identity := &binaryTestValues{
+		new(G1).ScalarBaseMult(zero),
+		new(G2).ScalarBaseMult(zero),
+		new(GT).ScalarBaseMult(zero),
+	}
+	if data, err = json.Marshal(identity); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.A.p.IsInfinity() || !got.B.p.IsInfinity() || !got.C.p.IsOne() {
+		t.Fatalf("identity elements decoded as %v", got)
+	}
+
+	// null leaves a value that isn't behind a pointer alone.
+	var value struct{ A G1 }
+	value.A.Set(want.A)
+	if err := json.Unmarshal([]byte(`{"A": null}`), &value); err != nil || !value.A.Equal(want.A) {
+		t.Fatalf("null decoded as %v, %v", &value.A, err)
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	m1, _ := json.Marshal(new(G1).ScalarBaseMult(big.NewInt(1)))
+	short := `"` + string(m1[1:len(m1)-5]) + `"`
+
+	tests := map[string]string{
+		"number":       `{"A": 1}`,
+		"bad base64":   `{"A": "not base64!"}`,
+		"wrong length": `{"A": "AAAA"}`,
+		"G2 length":    `{"B": "AAAA"}`,
+		"GT length":    `{"C": "AAAA"}`,
+		"G1 short":     `{"A": ` + short + `}`,
+		"malformed G2": `{"B": "Ag=="}`,
+	}
+	for name, data := range tests {
+		if err := json.Unmarshal([]byte(data), new(binaryTestValues)); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package bn256
+
+import (
+	"io"
+	"math/big"
+)
+
+// BlindPair returns e(r·p, q) for a random, non-zero r read from rand,
+// together with r⁻¹ mod Order. Since
+//
+//	e(r·p, q) = e(p, q)ʳ
+//
+// the party that computes the pairing learns nothing about e(p, q) from the
+// blinded value, and Unblind(blinded, unblind) recovers it.
+func BlindPair(p *G1, q *G2, rand io.Reader) (blinded *GT, unblind *big.Int, err error) {
+	r, err := randomK(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	blinded = Pair(new(G1).ScalarMult(p, r), q)
+	return blinded, r.ModInverse(r, Order), nil
+}
+
+// Unblind returns blinded raised to unblind, which undoes the blinding of
+// BlindPair when unblind is the value it returned: (e(p, q)ʳ)^(r⁻¹) = e(p, q).
+func Unblind(blinded *GT, unblind *big.Int) *GT {
+	return new(GT).ScalarMult(blinded, unblind)
+}
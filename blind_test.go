@@ -0,0 +1,37 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestBlindPair(t *testing.T) {
+	_, p, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG2(rand.Reader)
+	want := Pair(p, q)
+
+	blinded, unblind, err := BlindPair(p, q, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blinded.Equal(want) {
+		t.Fatal("blinded pairing equals the pairing")
+	}
+	if !Unblind(blinded, unblind).Equal(want) {
+		t.Fatal("unblinded pairing doesn't match")
+	}
+
+	r := new(big.Int).ModInverse(unblind, Order)
+	if !blinded.Equal(new(GT).ScalarMult(want, r)) {
+		t.Fatal("blinded pairing isn't e(p, q)^r")
+	}
+
+	blinded2, _, err := BlindPair(p, q, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blinded.Equal(blinded2) {
+		t.Fatal("blinding factors repeat")
+	}
+}
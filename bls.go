@@ -0,0 +1,48 @@
+package bn256
+
+// BLSSignatureDST is the domain separation tag with which messages are hashed
+// to G₁ for the BLS signatures verified by this package. A signature of msg
+// under the secret key k, whose public key is k·g₂ in G₂, is
+// HashG1(msg, []byte(BLSSignatureDST)) multiplied by k.
+const BLSSignatureDST = "BN256-BLS-SIGNATURE-G1"
+
+// VerifyAggregateDistinct reports whether aggSig is a valid aggregate of the
+// BLS signatures of msgs[i] under the public keys pks[i], that is whether
+//
+//	e(aggSig, g₂) = ∏ e(H(msgs[i]), pks[i])
+//
+// It is checked as a single product of pairings, with aggSig negated, so only
+// one final exponentiation is needed.
+//
+// Aggregates are only secure against rogue-key attacks if either every public
+// key comes with a proof of possession of its secret key, or all the messages
+// are distinct. VerifyAggregateDistinct enforces the latter and returns false
+// if any message is repeated. It also returns false if len(pks) != len(msgs),
+// if there are no messages or if any public key is the point at infinity. The
+// caller must make sure that the public keys are in G₂, for example by
+// decoding them with Unmarshal.
+func VerifyAggregateDistinct(aggSig *G1, pks []*G2, msgs [][]byte) bool {
+	if len(pks) != len(msgs) || len(msgs) == 0 {
+		return false
+	}
+
+	seen := make(map[string]bool, len(msgs))
+	a := make([]*curvePoint, 0, len(msgs)+1)
+	b := make([]*twistPoint, 0, len(msgs)+1)
+	for i, msg := range msgs {
+		if seen[string(msg)] || pks[i].p.IsInfinity() {
+			return false
+		}
+		seen[string(msg)] = true
+
+		a = append(a, HashG1(msg, []byte(BLSSignatureDST)).p)
+		b = append(b, pks[i].p)
+	}
+
+	negSig := &curvePoint{}
+	negSig.Neg(aggSig.p)
+	a = append(a, negSig)
+	b = append(b, twistGen)
+
+	return pairingCheck(a, b)
+}
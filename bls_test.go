@@ -0,0 +1,51 @@
+package bn256
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+func TestVerifyAggregateDistinct(t *testing.T) {
+	const n = 4
+	pks := make([]*G2, n)
+	msgs := make([][]byte, n)
+	agg := new(G1).ScalarBaseMult(new(big.Int))
+	for i := range pks {
+		var k *big.Int
+		k, pks[i], _ = RandomG2(rand.Reader)
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+
+		sig := HashG1(msgs[i], []byte(BLSSignatureDST))
+		sig.ScalarMult(sig, k)
+		agg.Add(agg, sig)
+	}
+
+	if !VerifyAggregateDistinct(agg, pks, msgs) {
+		t.Fatal("valid aggregate was rejected")
+	}
+
+	tampered := new(G1).Add(agg, &G1{curveGen})
+	if VerifyAggregateDistinct(tampered, pks, msgs) {
+		t.Fatal("tampered aggregate was accepted")
+	}
+
+	swapped := append([]*G2{pks[1], pks[0]}, pks[2:]...)
+	if VerifyAggregateDistinct(agg, swapped, msgs) {
+		t.Fatal("aggregate was accepted with swapped public keys")
+	}
+
+	if VerifyAggregateDistinct(agg, pks[:n-1], msgs[:n-1]) {
+		t.Fatal("aggregate was accepted with a missing signer")
+	}
+	if VerifyAggregateDistinct(agg, pks, msgs[:n-1]) {
+		t.Fatal("mismatched lengths were accepted")
+	}
+
+	repeated := append([][]byte{msgs[0]}, msgs[:n-1]...)
+	if VerifyAggregateDistinct(agg, pks, repeated) {
+		t.Fatal("repeated message was accepted")
+	}
+}
@@ -17,21 +17,34 @@
 package bn256
 
 import (
-	"crypto/rand"
 	"errors"
 	"io"
 	"math/big"
 )
 
-func randomK(r io.Reader) (k *big.Int, err error) {
+// randomK returns a scalar chosen uniformly from [1, Order-1] with bytes read
+// from r. It reads ScalarSize bytes at a time, clears the bits above
+// Order.BitLen() like crypto/rand.Int does, and rejects the values that are
+// zero or not less than Order, instead of reducing them, which would make the
+// small scalars more likely than the others. The result only depends on the
+// bytes r returns, so a deterministic r yields reproducible scalars.
+//
+// Order is just above 2²⁵⁵, so Order.BitLen() is 256 and the mask keeps every
+// bit: about 44% of the reads are rejected, as with crypto/rand.Int.
+func randomK(r io.Reader) (*big.Int, error) {
+	var buf [ScalarSize]byte
+	mask := byte(0xff >> uint(8*ScalarSize-Order.BitLen()))
+	k := new(big.Int)
 	for {
-		k, err = rand.Int(r, Order)
-		if err != nil || k.Sign() > 0 {
-			return
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		buf[0] &= mask
+		k.SetBytes(buf[:])
+		if k.Sign() > 0 && k.Cmp(Order) < 0 {
+			return k, nil
 		}
 	}
-
-	return
 }
 
 // G1 is an abstract cyclic group. The zero value is suitable for use as the
@@ -41,6 +54,9 @@ type G1 struct {
 }
 
 // RandomG1 returns x and g₁ˣ where x is a random, non-zero number read from r.
+// x is uniform in [1, Order-1] and only depends on the bytes read from r, so
+// a deterministic reader, such as one seeded for a test, gives reproducible
+// results.
 func RandomG1(r io.Reader) (*big.Int, *G1, error) {
 	k, err := randomK(r)
 	if err != nil {
@@ -55,21 +71,37 @@ func (g *G1) String() string {
 }
 
 // ScalarBaseMult sets e to g*k where g is the generator of the group and then
-// returns e.
+// returns e. k is reduced modulo Order. It uses a table of multiples of g that
+// is built on first use, unless built with the bn256small tag, and runs in
+// constant time like ScalarMultCT; see PrecomputeG1Generator.
 func (e *G1) ScalarBaseMult(k *big.Int) *G1 {
 	if e.p == nil {
 		e.p = &curvePoint{}
 	}
-	e.p.Mul(curveGen, k)
+	g1BaseMult(e.p, k)
 	return e
 }
 
-// ScalarMult sets e to a*k and then returns e.
+// ScalarMult sets e to a*k and then returns e. k is reduced modulo Order
+// first, so any integer is accepted: a negative k gives the negation of
+// a*|k|, k and k+Order give the same point and a multiple of Order, zero
+// included, gives the point at infinity.
 func (e *G1) ScalarMult(a *G1, k *big.Int) *G1 {
 	if e.p == nil {
 		e.p = &curvePoint{}
 	}
-	e.p.Mul(a.p, k)
+	e.p.Mul(a.p, modOrder(k))
+	return e
+}
+
+// ScalarMultCT sets e to a*k and then returns e. Unlike ScalarMult, the
+// sequence of group operations, branches and memory accesses doesn't depend
+// on k, so it is meant for secret scalars. k is reduced modulo Order first.
+func (e *G1) ScalarMultCT(a *G1, k *big.Int) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.MulCT(a.p, k)
 	return e
 }
 
@@ -91,6 +123,29 @@ func (e *G1) Neg(a *G1) *G1 {
 	return e
 }
 
+// Double sets e to 2a, or a+a, and then returns e. The double of the point at
+// infinity is the point at infinity.
+func (e *G1) Double(a *G1) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Double(a.p)
+	return e
+}
+
+// NegateAllG1 negates every point of points in place, for example to move
+// one side of a pairing-product equation to the other. nil entries and zero
+// values are skipped, as PairingProduct treats them as the point at infinity,
+// which is its own negation.
+func NegateAllG1(points []*G1) {
+	for _, p := range points {
+		if p == nil || p.p == nil {
+			continue
+		}
+		p.p.Neg(p.p)
+	}
+}
+
 // Set sets e to a and then returns e.
 func (e *G1) Set(a *G1) *G1 {
 	if e.p == nil {
@@ -100,8 +155,35 @@ func (e *G1) Set(a *G1) *G1 {
 	return e
 }
 
-// Marshal converts e to a byte slice.
+// Equal reports whether e and a are the same point. The points don't need to
+// be normalized: their Jacobian coordinates are cross-multiplied. It runs in
+// constant time, so it can be used on secret points.
+func (e *G1) Equal(a *G1) bool {
+	return e.p.Equal(a.p)
+}
+
+// IsNegationOf reports whether e is -a, which is the case for the point at
+// infinity and itself. Like Equal, it compares the points without normalizing
+// them. It helps to track down a missing negation in a pairing check.
+func (e *G1) IsNegationOf(a *G1) bool {
+	neg := &curvePoint{}
+	neg.Neg(a.p)
+	return e.p.Equal(neg)
+}
+
+// Marshal converts e to a byte slice. The coordinates are converted out of
+// Montgomery form and written as big-endian numbers less than p, so every
+// representation of a point has the same encoding. The conversion, including
+// the inversion into affine form, runs in constant time, so Marshal can be
+// used on points derived from secrets. Points that are already affine skip
+// the inversion, as whether z = 1 doesn't depend on the point's value.
 func (e *G1) Marshal() []byte {
+	return e.marshalTo(make([]byte, g1Size))
+}
+
+// marshalTo writes the output of Marshal to ret, which must be g1Size bytes
+// long, and returns it.
+func (e *G1) marshalTo(ret []byte) []byte {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
 
@@ -109,24 +191,64 @@ func (e *G1) Marshal() []byte {
 		e.p = &curvePoint{}
 	}
 
-	e.p.MakeAffine()
-	ret := make([]byte, numBytes*2)
-	if e.p.IsInfinity() {
-		return ret
-	}
+	// The conversion doesn't branch on the point: the point at infinity,
+	// whose affine form is (0, 1), is written as all zeros by clearing y
+	// with gfpCMov. montDecode reduces its output fully, so the coordinates
+	// are always less than p.
+	infinity := e.p.makeAffineCT()
 	temp := &gfP{}
 
 	montDecode(temp, &e.p.x)
 	temp.Marshal(ret)
 	montDecode(temp, &e.p.y)
+	gfpCMov(temp, temp, &gfP{}, infinity)
 	temp.Marshal(ret[numBytes:])
 
+	if debug {
+		checkRoundTripG1(e, ret)
+	}
 	return ret
 }
 
+// XY returns the affine coordinates of e, reduced modulo p, for passing a
+// point to other libraries or to smart-contract precompiles. The point at
+// infinity, which has no affine coordinates, is returned as (0, 0), which is
+// not on the curve, as in the encoding of Marshal. XY converts e to affine
+// form, like Marshal.
+func (e *G1) XY() (x, y *big.Int) {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.MakeAffine()
+	if e.p.IsInfinity() {
+		return new(big.Int), new(big.Int)
+	}
+	return e.p.x.bigInt(), e.p.y.bigInt()
+}
+
 // Unmarshal sets e to the result of converting the output of Marshal back into
-// a group element and then returns e.
+// a group element and returns the rest of m, so that concatenated encodings
+// can be read one after the other; len(m) - len(rest) bytes were consumed.
+//
+// It returns an error, and leaves e unchanged, if m is too short, if a
+// coordinate is not less than p or if the point is not on the curve. The
+// range and curve checks run in constant time.
 func (e *G1) Unmarshal(m []byte) ([]byte, error) {
+	return e.unmarshal(m, true)
+}
+
+// UnmarshalTrusted is like Unmarshal, but it only reads the coordinates and
+// doesn't check that they describe a valid point.
+//
+// WARNING: UnmarshalTrusted must only be used on data that this process, or
+// another trusted party, produced with Marshal and stored somewhere it can't
+// have been tampered with, such as an internal cache. Using it on data from
+// the network or any other untrusted source allows invalid-curve attacks.
+func (e *G1) UnmarshalTrusted(m []byte) ([]byte, error) {
+	return e.unmarshal(m, false)
+}
+
+func (e *G1) unmarshal(m []byte, validate bool) ([]byte, error) {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
 
@@ -134,32 +256,33 @@ func (e *G1) Unmarshal(m []byte) ([]byte, error) {
 		return nil, errors.New("bn256: not enough data")
 	}
 
+	c := &curvePoint{}
+	c.x.Unmarshal(m)
+	c.y.Unmarshal(m[numBytes:])
+	inRange := lessThanPCT(&c.x) & lessThanPCT(&c.y)
+	montEncode(&c.x, &c.x)
+	montEncode(&c.y, &c.y)
+
+	// (0, 0) is the point at infinity. It is selected without branching, so
+	// that the time Unmarshal takes doesn't depend on the coordinates.
+	zero, one := &gfP{0}, newGFp(1)
+	infinity := gfpEqual(&c.x, zero) & gfpEqual(&c.y, zero)
+	onCurve := c.isOnCurveAffineCT() | infinity
+	gfpCMov(&c.y, &c.y, one, infinity)
+	gfpCMov(&c.z, one, zero, infinity)
+	gfpCMov(&c.t, one, zero, infinity)
+
+	if validate && inRange != 1 {
+		return nil, errors.New("bn256: coordinate not less than p")
+	}
+	if validate && onCurve != 1 {
+		return nil, errors.New("bn256: malformed point")
+	}
+
 	if e.p == nil {
 		e.p = &curvePoint{}
-	} else {
-		e.p.x, e.p.y = gfP{0}, gfP{0}
-	}
-
-	e.p.x.Unmarshal(m)
-	e.p.y.Unmarshal(m[numBytes:])
-	montEncode(&e.p.x, &e.p.x)
-	montEncode(&e.p.y, &e.p.y)
-
-	zero := gfP{0}
-	if e.p.x == zero && e.p.y == zero {
-		// This is the point at infinity.
-		e.p.y = *newGFp(1)
-		e.p.z = gfP{0}
-		e.p.t = gfP{0}
-	} else {
-		e.p.z = *newGFp(1)
-		e.p.t = *newGFp(1)
-
-		if !e.p.IsOnCurve() {
-			return nil, errors.New("bn256: malformed point")
-		}
 	}
-
+	e.p.Set(c)
 	return m[2*numBytes:], nil
 }
 
@@ -170,6 +293,7 @@ type G2 struct {
 }
 
 // RandomG2 returns x and g₂ˣ where x is a random, non-zero number read from r.
+// x is chosen like in RandomG1.
 func RandomG2(r io.Reader) (*big.Int, *G2, error) {
 	k, err := randomK(r)
 	if err != nil {
@@ -184,21 +308,40 @@ func (e *G2) String() string {
 }
 
 // ScalarBaseMult sets e to g*k where g is the generator of the group and then
-// returns out.
+// returns out. k is reduced modulo Order. It uses a table of multiples of g
+// that is built on first use, unless built with the bn256small tag, and runs
+// in constant time like ScalarMultCT; see PrecomputeG2Generator.
 func (e *G2) ScalarBaseMult(k *big.Int) *G2 {
 	if e.p == nil {
 		e.p = &twistPoint{}
 	}
-	e.p.Mul(twistGen, k)
+	g2BaseMult(e.p, k)
 	return e
 }
 
-// ScalarMult sets e to a*k and then returns e.
+// ScalarMult sets e to a*k and then returns e. A negative k gives the
+// negation of a*|k|. k is not reduced modulo Order, so the result is correct
+// for any point of the twist, and ScalarMult(a, Order) is the point at
+// infinity only if a is in G₂.
 func (e *G2) ScalarMult(a *G2, k *big.Int) *G2 {
 	if e.p == nil {
 		e.p = &twistPoint{}
 	}
-	e.p.Mul(a.p, k)
+	e.p.Mul(a.p, new(big.Int).Abs(k))
+	if k.Sign() < 0 {
+		e.p.Neg(e.p)
+	}
+	return e
+}
+
+// ScalarMultCT sets e to a*k and then returns e. Unlike ScalarMult, the
+// sequence of group operations, branches and memory accesses doesn't depend
+// on k, so it is meant for secret scalars. k is reduced modulo Order first.
+func (e *G2) ScalarMultCT(a *G2, k *big.Int) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.MulCT(a.p, k)
 	return e
 }
 
@@ -220,6 +363,29 @@ func (e *G2) Neg(a *G2) *G2 {
 	return e
 }
 
+// Double sets e to 2a, or a+a, and then returns e. The double of the point at
+// infinity is the point at infinity.
+func (e *G2) Double(a *G2) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Double(a.p)
+	return e
+}
+
+// NegateAllG2 negates every point of points in place, for example to move
+// one side of a pairing-product equation to the other. nil entries and zero
+// values are skipped, as PairingProduct treats them as the point at infinity,
+// which is its own negation.
+func NegateAllG2(points []*G2) {
+	for _, p := range points {
+		if p == nil || p.p == nil {
+			continue
+		}
+		p.p.Neg(p.p)
+	}
+}
+
 // Set sets e to a and then returns e.
 func (e *G2) Set(a *G2) *G2 {
 	if e.p == nil {
@@ -229,8 +395,32 @@ func (e *G2) Set(a *G2) *G2 {
 	return e
 }
 
-// Marshal converts e into a byte slice.
+// Equal reports whether e and a are the same point. The points don't need to
+// be normalized: their Jacobian coordinates are cross-multiplied. It runs in
+// constant time, so it can be used on secret points.
+func (e *G2) Equal(a *G2) bool {
+	return e.p.Equal(a.p)
+}
+
+// IsNegationOf reports whether e is -a, which is the case for the point at
+// infinity and itself. Like Equal, it compares the points without normalizing
+// them. It helps to track down a missing negation in a pairing check.
+func (e *G2) IsNegationOf(a *G2) bool {
+	neg := &twistPoint{}
+	neg.Neg(a.p)
+	return e.p.Equal(neg)
+}
+
+// Marshal converts e into a byte slice. Like G1.Marshal, it writes canonical
+// coordinates and runs in constant time, except that the length of the output
+// shows whether e is the point at infinity.
 func (e *G2) Marshal() []byte {
+	return e.marshalTo(make([]byte, g2Size))
+}
+
+// marshalTo writes the output of Marshal to buf, which must be at least
+// g2Size bytes long, and returns the written prefix of buf.
+func (e *G2) marshalTo(buf []byte) []byte {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
 
@@ -238,12 +428,12 @@ func (e *G2) Marshal() []byte {
 		e.p = &twistPoint{}
 	}
 
-	e.p.MakeAffine()
-	if e.p.IsInfinity() {
-		return make([]byte, 1)
-	}
+	// The conversion doesn't branch on the point, like that of G1.Marshal.
+	// Only the length of the output, which is a single byte for the point at
+	// infinity, is chosen at the end.
+	infinity := e.p.makeAffineCT()
 
-	ret := make([]byte, 1+numBytes*4)
+	ret := buf[:1+numBytes*4]
 	ret[0] = 0x01
 	temp := &gfP{}
 
@@ -256,62 +446,121 @@ func (e *G2) Marshal() []byte {
 	montDecode(temp, &e.p.y.y)
 	temp.Marshal(ret[1+3*numBytes:])
 
+	if infinity == 1 {
+		ret = buf[:1]
+		ret[0] = 0x00
+	}
+	if debug {
+		checkRoundTripG2(e, ret)
+	}
 	return ret
 }
 
+// XY returns the affine coordinates x = x0 + x1·i and y = y0 + y1·i of e,
+// each component reduced modulo p. Note that x1 and y1, the imaginary parts,
+// are written first by Marshal. The point at infinity is returned as all
+// zeros, like G1.XY.
+func (e *G2) XY() (x0, x1, y0, y1 *big.Int) {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.MakeAffine()
+	if e.p.IsInfinity() {
+		return new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	}
+	return e.p.x.y.bigInt(), e.p.x.x.bigInt(), e.p.y.y.bigInt(), e.p.y.x.bigInt()
+}
+
 // Unmarshal sets e to the result of converting the output of Marshal back into
-// a group element and then returns e.
+// a group element and returns the rest of m, so that concatenated encodings
+// can be read one after the other; len(m) - len(rest) bytes were consumed.
+//
+// It returns an error, and leaves e unchanged, if m is too short, if a
+// coordinate is not less than p or if the point is not on the twist. Apart
+// from the leading byte, which marks the point at infinity, the range and
+// curve checks run in constant time. Unmarshal doesn't check that the point
+// is in G₂; see UnmarshalStrict.
 func (e *G2) Unmarshal(m []byte) ([]byte, error) {
+	return e.unmarshal(m, true)
+}
+
+// UnmarshalTrusted is like Unmarshal, but it only reads the coordinates and
+// doesn't check that they describe a valid point.
+//
+// WARNING: UnmarshalTrusted must only be used on data that this process, or
+// another trusted party, produced with Marshal and stored somewhere it can't
+// have been tampered with, such as an internal cache. Using it on data from
+// the network or any other untrusted source allows invalid-curve and
+// small-subgroup attacks.
+func (e *G2) UnmarshalTrusted(m []byte) ([]byte, error) {
+	return e.unmarshal(m, false)
+}
+
+func (e *G2) unmarshal(m []byte, validate bool) ([]byte, error) {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
 
-	if e.p == nil {
-		e.p = &twistPoint{}
+	if len(m) == 0 {
+		return nil, errors.New("bn256: not enough data")
 	}
-
-	if len(m) > 0 && m[0] == 0x00 {
+	if m[0] == 0x00 {
+		if e.p == nil {
+			e.p = &twistPoint{}
+		}
 		e.p.SetInfinity()
 		return m[1:], nil
-	} else if len(m) > 0 && m[0] != 0x01 {
+	} else if m[0] != 0x01 {
 		return nil, errors.New("bn256: malformed point")
 	} else if len(m) < 1+4*numBytes {
 		return nil, errors.New("bn256: not enough data")
 	}
 
-	e.p.x.x.Unmarshal(m[1:])
-	e.p.x.y.Unmarshal(m[1+numBytes:])
-	e.p.y.x.Unmarshal(m[1+2*numBytes:])
-	e.p.y.y.Unmarshal(m[1+3*numBytes:])
-	montEncode(&e.p.x.x, &e.p.x.x)
-	montEncode(&e.p.x.y, &e.p.x.y)
-	montEncode(&e.p.y.x, &e.p.y.x)
-	montEncode(&e.p.y.y, &e.p.y.y)
-
-	if e.p.x.IsZero() && e.p.y.IsZero() {
-		// This is the point at infinity.
-		e.p.y.SetOne()
-		e.p.z.SetZero()
-		e.p.t.SetZero()
-	} else {
-		e.p.z.SetOne()
-		e.p.t.SetOne()
-
-		if !e.p.IsOnCurve() {
-			return nil, errors.New("bn256: malformed point")
-		}
+	c := &twistPoint{}
+	c.x.x.Unmarshal(m[1:])
+	c.x.y.Unmarshal(m[1+numBytes:])
+	c.y.x.Unmarshal(m[1+2*numBytes:])
+	c.y.y.Unmarshal(m[1+3*numBytes:])
+	inRange := lessThanPCT(&c.x.x) & lessThanPCT(&c.x.y) &
+		lessThanPCT(&c.y.x) & lessThanPCT(&c.y.y)
+	montEncode(&c.x.x, &c.x.x)
+	montEncode(&c.x.y, &c.x.y)
+	montEncode(&c.y.x, &c.y.x)
+	montEncode(&c.y.y, &c.y.y)
+
+	// See G1.unmarshal.
+	zero, one := &gfP2{}, (&gfP2{}).SetOne()
+	infinity := gfp2Equal(&c.x, zero) & gfp2Equal(&c.y, zero)
+	onCurve := c.isOnCurveAffineCT() | infinity
+	gfp2CMov(&c.y, &c.y, one, infinity)
+	gfp2CMov(&c.z, one, zero, infinity)
+	gfp2CMov(&c.t, one, zero, infinity)
+
+	if validate && inRange != 1 {
+		return nil, errors.New("bn256: coordinate not less than p")
+	}
+	if validate && onCurve != 1 {
+		return nil, errors.New("bn256: malformed point")
 	}
 
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Set(c)
 	return m[1+4*numBytes:], nil
 }
 
 // GT is an abstract cyclic group. The zero value is suitable for use as the
 // output of an operation, but cannot be used as an input.
+//
+// GT values must not be compared with ==: a GT holds a pointer, so == on two
+// GT (or *GT) values compares pointers and reports equal elements computed
+// separately as different. Use Equal, or compare the output of Canonical.
 type GT struct {
 	p *gfP12
 }
 
 // RandomGT returns x and e(g₁, g₂)ˣ where x is a random, non-zero number read
-// from r.
+// from r. x is chosen like in RandomG1.
 func RandomGT(r io.Reader) (*big.Int, *GT, error) {
 	k, err := randomK(r)
 	if err != nil {
@@ -326,33 +575,249 @@ func Pair(g1 *G1, g2 *G2) *GT {
 	return &GT{optimalAte(g2.p, g1.p)}
 }
 
+// PairingProduct calculates ∏ e(a[i], b[i]). The Miller loops of all pairs
+// run together and share both their squarings and a single final
+// exponentiation, which makes it much cheaper than multiplying the results of
+// separate Pair calls; see BenchmarkPairingProduct. Large products are split
+// into chunks whose Miller loops run on up to GOMAXPROCS goroutines; see
+// BenchmarkPairingProductParallel. Pairs in which either point is nil or the
+// point at infinity contribute one, and the product of no pairs is one. It
+// panics if a and b have different lengths.
+func PairingProduct(a []*G1, b []*G2) *GT {
+	ps, qs := pairingInputs(a, b)
+	return &GT{finalExponentiation(multiMiller(qs, ps))}
+}
+
+// PairingCheck reports whether ∏ e(a[i], b[i]) is one, which is how most
+// pairing-based signatures are verified: e(σ, g₂) = e(H(m), pk) is checked as
+// PairingCheck([-σ, H(m)], [g₂, pk]). Like PairingProduct it runs a single
+// Miller loop for all pairs and one final exponentiation, and it treats nil
+// points and points at infinity the same way. The product of no pairs is one,
+// so PairingCheck(nil, nil) is true. It panics if a and b have different
+// lengths.
+func PairingCheck(a []*G1, b []*G2) bool {
+	ps, qs := pairingInputs(a, b)
+	return pairingCheck(ps, qs)
+}
+
+// PairingTerm is one pairing e(G1, G2) of a product checked by
+// VerifyPairingProduct.
+type PairingTerm struct {
+	G1 *G1
+	G2 *G2
+}
+
+// VerifyPairingProduct reports whether ∏ e(terms[i].G1, terms[i].G2) equals
+// target, or one if target is nil. Groth16 verification, for example, checks
+// e(A, B) = e(α, β)·e(L, γ)·e(C, δ), which is
+//
+//	VerifyPairingProduct([]PairingTerm{{A, B}, {-L, γ}, {-C, δ}}, e(α, β))
+//
+// with e(α, β) precomputed. All terms share a single Miller loop and final
+// exponentiation, after which the result is compared to target, which is
+// already in GT and so doesn't have to be moved into the product. Terms with
+// a nil point or a point at infinity contribute one.
+func VerifyPairingProduct(terms []PairingTerm, target *GT) bool {
+	a, b := make([]*G1, len(terms)), make([]*G2, len(terms))
+	for i, term := range terms {
+		a[i], b[i] = term.G1, term.G2
+	}
+	ps, qs := pairingInputs(a, b)
+	product := finalExponentiation(multiMiller(qs, ps))
+	if target == nil {
+		return product.IsOne()
+	}
+	return *product == *target.p
+}
+
+// pairingInputs returns the points of a and b, leaving out the pairs in which
+// either point is nil.
+func pairingInputs(a []*G1, b []*G2) ([]*curvePoint, []*twistPoint) {
+	if len(a) != len(b) {
+		panic("bn256: number of G1 points doesn't match the number of G2 points")
+	}
+
+	ps := make([]*curvePoint, 0, len(a))
+	qs := make([]*twistPoint, 0, len(b))
+	for i := range a {
+		if a[i] == nil || a[i].p == nil || b[i] == nil || b[i].p == nil {
+			continue
+		}
+		ps = append(ps, a[i].p)
+		qs = append(qs, b[i].p)
+	}
+	return ps, qs
+}
+
+// PairExp calculates e(g1, g2)ᵏ, with k reduced modulo Order. The pairing
+// result is exponentiated in place with the windowed, cyclotomic-squaring
+// exponentiation of GTExpProduct, which is cheaper than GT.ScalarMult.
+// PairExpG1 computes the same value as e(k·g1, g2), which is faster still on
+// amd64; see BenchmarkPairExp.
+func PairExp(g1 *G1, g2 *G2, k *big.Int) *GT {
+	return GTExpProduct([]*GT{Pair(g1, g2)}, []*big.Int{k})
+}
+
+// PairExpG1 calculates e(g1, g2)ᵏ as e(k·g1, g2). The scalar multiplication in
+// G₁ is usually cheaper than an exponentiation in GT. Like PairExp, it reduces
+// k modulo Order.
+func PairExpG1(g1 *G1, g2 *G2, k *big.Int) *GT {
+	return Pair(new(G1).ScalarMult(g1, k), g2)
+}
+
+// PairInverse calculates e(g1, g2)⁻¹. By bilinearity e(g1, g2)⁻¹ = e(-g1, g2),
+// so this only negates g1 before pairing, which is much cheaper than
+// inverting the result in GT.
+func PairInverse(g1 *G1, g2 *G2) *GT {
+	neg := &curvePoint{}
+	neg.Neg(g1.p)
+	return &GT{optimalAte(g2.p, neg)}
+}
+
+// PairScalars returns e(a·g₁, b·g₂), where g₁ and g₂ are the generators of G₁
+// and G₂. By bilinearity this is e(g₁, g₂)^(a·b), so it is computed as a
+// single fixed-base exponentiation in GT without any scalar multiplication or
+// pairing. It only applies when both points are known as multiples of the
+// generators.
+func PairScalars(a, b *big.Int) *GT {
+	ab := new(big.Int).Mul(a, b)
+	return new(GT).ScalarBaseMult(ab.Mod(ab, Order))
+}
+
+// CheckBilinear reports whether e(a·p, b·q) = e(p, q)^(a·b), the defining
+// property of the pairing. It is a self-test for downstream test suites and
+// fuzzers: it computes both sides independently, with ScalarMult on each
+// group, two pairings and GT.Exp, so bugs in the field arithmetic or in any
+// of those operations make it return false for most inputs. Scalars may be
+// zero or negative; a zero scalar gives one on both sides.
+func CheckBilinear(a, b *big.Int, p *G1, q *G2) bool {
+	ab := new(big.Int).Mul(a, b)
+	return checkBilinear(a, b, ab, p, q)
+}
+
+// checkBilinear reports whether e(a·p, b·q) = e(p, q)^ab.
+func checkBilinear(a, b, ab *big.Int, p *G1, q *G2) bool {
+	lhs := Pair(new(G1).ScalarMult(p, a), new(G2).ScalarMult(q, b))
+	rhs := new(GT).Exp(Pair(p, q), modOrder(ab))
+	return lhs.Equal(rhs)
+}
+
 // Miller applies Miller's algorithm, which is a bilinear function from the
 // source groups to F_p^12. Miller(g1, g2).Finalize() is equivalent to Pair(g1,
 // g2).
+//
+// Deprecated: use MillerLoop, which returns the same value and documents
+// what may be done with it.
 func Miller(g1 *G1, g2 *G2) *GT {
 	return &GT{miller(g2.p, g1.p)}
 }
 
+// MillerLoop returns the output of the Miller loop of the Optimal Ate pairing
+// of g1 and g2, before the final exponentiation.
+//
+// The result is NOT an element of GT: it is an element of GF(p¹²) that is
+// only determined up to factors that the final exponentiation removes, so it
+// must not be compared, encoded, or passed to any method that expects an
+// element of GT, such as Exp or ScalarMultCT. The only meaningful operations
+// on it are Add, to multiply Miller loop outputs, and FinalExponentiation,
+// which turns it into a proper element of GT. Protocols that aggregate many
+// pairings can so do a single final exponentiation:
+//
+//	m := MillerLoop(a, b)
+//	m.Add(m, MillerLoop(c, d)).FinalExponentiation()
+//
+// equals Pair(a, b) + Pair(c, d). PairingProduct does this for a whole slice
+// of pairs, and shares the squarings of the Miller loops too.
+func MillerLoop(g1 *G1, g2 *G2) *GT {
+	return &GT{miller(g2.p, g1.p)}
+}
+
 func (g *GT) String() string {
 	return "bn256.GT" + g.p.String()
 }
 
 // ScalarBaseMult sets e to g*k where g is the generator of the group and then
-// returns out.
+// returns out. It uses a table of powers of g that is built on first use,
+// unless built with the bn256small tag; see PrecomputeGTGenerator.
 func (e *GT) ScalarBaseMult(k *big.Int) *GT {
 	if e.p == nil {
 		e.p = &gfP12{}
 	}
-	e.p.Exp(gfP12Gen, k)
+	gtBaseExp(e.p, k)
 	return e
 }
 
-// ScalarMult sets e to a*k and then returns e.
+// ScalarMult sets e to a*k and then returns e. A negative k gives the inverse
+// of a*|k|. k is not reduced modulo Order, so the result is correct for any
+// element of GF(p¹²), and ScalarMult(a, Order) is one only if a is in GT.
 func (e *GT) ScalarMult(a *GT, k *big.Int) *GT {
 	if e.p == nil {
 		e.p = &gfP12{}
 	}
-	e.p.Exp(a.p, k)
+	e.p.Exp(a.p, new(big.Int).Abs(k))
+	if k.Sign() < 0 {
+		e.p.Invert(e.p)
+	}
+	return e
+}
+
+// Exp sets e to a*k and then returns e. It is faster than ScalarMult, as it
+// uses the cheaper squarings of the cyclotomic subgroup and inverts by
+// conjugation, but a must be an element of GT, such as a pairing result, and
+// not an unfinalized Miller loop output. Negative values of k invert the
+// result. Its running time depends on k; see ScalarMultCT for secret
+// exponents.
+func (e *GT) Exp(a *GT, k *big.Int) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.ExpCyclo6(a.p, k)
+	return e
+}
+
+// ScalarMultCT sets e to a*k and then returns e. Unlike ScalarMult, the
+// sequence of operations, branches and memory accesses doesn't depend on k, so
+// it is meant for secret exponents, such as in IBE private-key operations. k
+// is reduced modulo Order first. a must be in GT, as every output of Pair is;
+// see GT.IsInSubGroup.
+func (e *GT) ScalarMultCT(a *GT, k *big.Int) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.expCT(a.p, k)
+	return e
+}
+
+// ExpU64 sets e to a*k and then returns e. It is a faster, allocation-free
+// alternative to ScalarMult for scalars that fit in a uint64. a must be an
+// element of GT, such as a pairing result, and not an unfinalized Miller loop
+// output.
+func (e *GT) ExpU64(a *GT, k uint64) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.expU64Cyclo6(a.p, k)
+	return e
+}
+
+// Sqrt sets e to the square root of a and then returns e. GT has odd order
+// Order, so every element a has exactly one square root in GT, which is
+// a^((Order+1)/2): its square is a^(Order+1) = a. Sqrt computes it with the
+// cyclotomic exponentiation of Exp.
+//
+// a must be an element of GT. Elements of GF(p¹²) outside of GT, such as the
+// output of Miller or of Unmarshal on untrusted data, have no such root, and
+// Sqrt panics if the square of its result isn't a. Use UnmarshalStrict or
+// IsValid to check elements first.
+func (e *GT) Sqrt(a *GT) *GT {
+	root := (&gfP12{}).ExpCyclo6(a.p, orderPlus1Over2)
+	if *(&gfP12{}).Square(root) != *a.p {
+		panic("bn256: square root of an element not in GT")
+	}
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Set(root)
 	return e
 }
 
@@ -374,6 +839,16 @@ func (e *GT) Neg(a *GT) *GT {
 	return e
 }
 
+// Double sets e to a+a, which in the multiplicative notation of GF(p¹²) is
+// a², and then returns e.
+func (e *GT) Double(a *GT) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Square(a.p)
+	return e
+}
+
 // Set sets e to a and then returns e.
 func (e *GT) Set(a *GT) *GT {
 	if e.p == nil {
@@ -383,15 +858,94 @@ func (e *GT) Set(a *GT) *GT {
 	return e
 }
 
-// Finalize is a linear function from F_p^12 to GT.
+// Equal reports whether e and a are the same element. Every coordinate is kept
+// fully reduced modulo p, so this is equivalent to comparing the outputs of
+// Canonical, without the encoding. It runs in constant time, so it can be
+// used on secret elements.
+func (e *GT) Equal(a *GT) bool {
+	return gfp12Equal(e.p, a.p) == 1
+}
+
+// FinalExponentiation returns e^((p¹²-1)/Order), which maps the output of
+// MillerLoop, or a product of such outputs, to an element of GT. Unlike
+// Finalize, it leaves e unchanged.
+func (e *GT) FinalExponentiation() *GT {
+	return &GT{finalExponentiation(e.p)}
+}
+
+// Finalize is a linear function from F_p^12 to GT. It sets e to
+// e^((p¹²-1)/Order) and then returns e.
+//
+// Deprecated: use FinalExponentiation, which computes the same value but
+// leaves e unchanged.
 func (e *GT) Finalize() *GT {
 	ret := finalExponentiation(e.p)
 	e.p.Set(ret)
 	return e
 }
 
+// EasyPart returns x^((p⁶-1)(p²+1)), the "easy part" of the final
+// exponentiation. The easy part is a homomorphism, so it can be applied to
+// Miller loop outputs before combining them. Its result lies in the
+// cyclotomic subgroup of GF(p¹²), which is what HardPart expects.
+func EasyPart(x *GT) *GT {
+	return &GT{finalExponentiationEasyPart(x.p)}
+}
+
+// CyclotomicProjection sets e to a^((p⁶-1)(p²+1)), like EasyPart, and then
+// returns e. This maps any non-zero element of GF(p¹²) into the cyclotomic
+// subgroup, of order p⁴-p²+1, where a^(p⁶) = a⁻¹ and the cheaper cyclotomic
+// squarings and subgroup checks apply. It is a homomorphism but not a
+// projection in the strict sense: on the cyclotomic subgroup it is
+// exponentiation by -2(p²+1), so elements already there are moved too. It is
+// computed with a conjugation, an inversion, a Frobenius map and two
+// multiplications. If a is zero, so is e.
+func (e *GT) CyclotomicProjection(a *GT) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Set(finalExponentiationEasyPart(a.p))
+	return e
+}
+
+// HardPart returns x^((p⁴-p²+1)/Order), the "hard part" of the final
+// exponentiation. x must be the output of EasyPart. HardPart(EasyPart(x)) is
+// the same as x.Finalize().
+func HardPart(x *GT) *GT {
+	return &GT{finalExponentiationHardPart(x.p)}
+}
+
+// Canonical returns the canonical encoding of e, which two elements share if
+// and only if they are equal. It is suitable for hashing pairing results into
+// a transcript, including across implementations.
+//
+// GF(p¹²) is built as the tower
+//
+//	GF(p²)  = GF(p)[i]/(i²+1)
+//	GF(p⁶)  = GF(p²)[τ]/(τ³-ξ), where ξ = i+3
+//	GF(p¹²) = GF(p⁶)[ω]/(ω²-τ)
+//
+// and e is written as a₁₁ω⁵ + a₁₀ω⁴ + … + a₁ω + a₀, or equivalently
+// (x₂τ² + x₁τ + x₀)ω + (y₂τ² + y₁τ + y₀) with xₖ, yₖ in GF(p²). The encoding is
+// the twelve GF(p) coordinates
+//
+//	x₂.i, x₂.1, x₁.i, x₁.1, x₀.i, x₀.1, y₂.i, y₂.1, y₁.i, y₁.1, y₀.i, y₀.1
+//
+// where c.i and c.1 are the imaginary and real parts of c, each reduced modulo
+// p and written as a 32-byte big-endian number, for a total of 384 bytes.
+// This is the same encoding produced by Marshal.
+func (e *GT) Canonical() []byte {
+	return e.Marshal()
+}
+
 // Marshal converts e into a byte slice.
 func (e *GT) Marshal() []byte {
+	return e.marshalTo(make([]byte, gtSize))
+}
+
+// marshalTo writes the output of Marshal to ret, which must be gtSize bytes
+// long, and returns it.
+func (e *GT) marshalTo(ret []byte) []byte {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
 
@@ -400,7 +954,6 @@ func (e *GT) Marshal() []byte {
 		e.p.SetOne()
 	}
 
-	ret := make([]byte, numBytes*12)
 	temp := &gfP{}
 
 	montDecode(temp, &e.p.x.x.x)
@@ -428,11 +981,19 @@ func (e *GT) Marshal() []byte {
 	montDecode(temp, &e.p.y.z.y)
 	temp.Marshal(ret[11*numBytes:])
 
+	if debug {
+		checkRoundTripGT(e, ret)
+	}
 	return ret
 }
 
 // Unmarshal sets e to the result of converting the output of Marshal back into
-// a group element and then returns e.
+// a group element and returns the rest of m, so that concatenated encodings
+// can be read one after the other; len(m) - len(rest) bytes were consumed.
+//
+// It returns an error, and leaves e unchanged, if m is too short or if a
+// coordinate is not less than p. The range checks run in constant time.
+// Unmarshal doesn't check that the element is in GT; see UnmarshalStrict.
 func (e *GT) Unmarshal(m []byte) ([]byte, error) {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
@@ -441,34 +1002,23 @@ func (e *GT) Unmarshal(m []byte) ([]byte, error) {
 		return nil, errors.New("bn256: not enough data")
 	}
 
+	g := &gfP12{}
+	inRange := uint64(1)
+	for i, v := range []*gfP{
+		&g.x.x.x, &g.x.x.y, &g.x.y.x, &g.x.y.y, &g.x.z.x, &g.x.z.y,
+		&g.y.x.x, &g.y.x.y, &g.y.y.x, &g.y.y.y, &g.y.z.x, &g.y.z.y,
+	} {
+		v.Unmarshal(m[i*numBytes:])
+		inRange &= lessThanPCT(v)
+		montEncode(v, v)
+	}
+	if inRange != 1 {
+		return nil, errors.New("bn256: coordinate not less than p")
+	}
+
 	if e.p == nil {
 		e.p = &gfP12{}
 	}
-
-	e.p.x.x.x.Unmarshal(m)
-	e.p.x.x.y.Unmarshal(m[numBytes:])
-	e.p.x.y.x.Unmarshal(m[2*numBytes:])
-	e.p.x.y.y.Unmarshal(m[3*numBytes:])
-	e.p.x.z.x.Unmarshal(m[4*numBytes:])
-	e.p.x.z.y.Unmarshal(m[5*numBytes:])
-	e.p.y.x.x.Unmarshal(m[6*numBytes:])
-	e.p.y.x.y.Unmarshal(m[7*numBytes:])
-	e.p.y.y.x.Unmarshal(m[8*numBytes:])
-	e.p.y.y.y.Unmarshal(m[9*numBytes:])
-	e.p.y.z.x.Unmarshal(m[10*numBytes:])
-	e.p.y.z.y.Unmarshal(m[11*numBytes:])
-	montEncode(&e.p.x.x.x, &e.p.x.x.x)
-	montEncode(&e.p.x.x.y, &e.p.x.x.y)
-	montEncode(&e.p.x.y.x, &e.p.x.y.x)
-	montEncode(&e.p.x.y.y, &e.p.x.y.y)
-	montEncode(&e.p.x.z.x, &e.p.x.z.x)
-	montEncode(&e.p.x.z.y, &e.p.x.z.y)
-	montEncode(&e.p.y.x.x, &e.p.y.x.x)
-	montEncode(&e.p.y.x.y, &e.p.y.x.y)
-	montEncode(&e.p.y.y.x, &e.p.y.y.x)
-	montEncode(&e.p.y.y.y, &e.p.y.y.y)
-	montEncode(&e.p.y.z.x, &e.p.y.z.x)
-	montEncode(&e.p.y.z.y, &e.p.y.z.y)
-
+	e.p.Set(g)
 	return m[12*numBytes:], nil
 }
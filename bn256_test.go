@@ -5,10 +5,225 @@ import (
 
 	"bytes"
 	"crypto/rand"
+	"fmt"
+	"math/big"
+	"math/bits"
+	mathrand "math/rand"
+	"runtime"
 
 	"golang.org/x/crypto/bn256"
 )
 
+func TestScalarMultReduction(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	scalars := map[string]*big.Int{
+		"k+r":   new(big.Int).Add(k, Order),
+		"k-r":   new(big.Int).Sub(k, Order),
+		"k+10r": new(big.Int).Add(k, new(big.Int).Mul(Order, big.NewInt(10))),
+	}
+	zeros := []*big.Int{new(big.Int), new(big.Int).Set(Order), new(big.Int).Neg(Order)}
+	minusK := new(big.Int).Neg(k)
+
+	_, a, _ := RandomG1(rand.Reader)
+	want1 := new(G1).ScalarMult(a, k)
+	for name, s := range scalars {
+		if !new(G1).ScalarMult(a, s).Equal(want1) {
+			t.Errorf("G1: %s doesn't match k", name)
+		}
+	}
+	if !new(G1).ScalarMult(a, minusK).Equal(new(G1).Neg(want1)) {
+		t.Error("G1: -k doesn't give the negation")
+	}
+	for _, z := range zeros {
+		if !new(G1).ScalarMult(a, z).p.IsInfinity() {
+			t.Errorf("G1: %v doesn't give the point at infinity", z)
+		}
+	}
+
+	_, b, _ := RandomG2(rand.Reader)
+	want2 := new(G2).ScalarMult(b, k)
+	for name, s := range scalars {
+		if !new(G2).ScalarMult(b, s).Equal(want2) {
+			t.Errorf("G2: %s doesn't match k", name)
+		}
+	}
+	if !new(G2).ScalarMult(b, minusK).Equal(new(G2).Neg(want2)) {
+		t.Error("G2: -k doesn't give the negation")
+	}
+	for _, z := range zeros {
+		if !new(G2).ScalarMult(b, z).p.IsInfinity() {
+			t.Errorf("G2: %v doesn't give the point at infinity", z)
+		}
+	}
+
+	_, c, _ := RandomGT(rand.Reader)
+	wantT := new(GT).ScalarMult(c, k)
+	for name, s := range scalars {
+		if !new(GT).ScalarMult(c, s).Equal(wantT) {
+			t.Errorf("GT: %s doesn't match k", name)
+		}
+	}
+	if !new(GT).ScalarMult(c, minusK).Equal(new(GT).Neg(wantT)) {
+		t.Error("GT: -k doesn't give the inverse")
+	}
+	for _, z := range zeros {
+		if !new(GT).ScalarMult(c, z).p.IsOne() {
+			t.Errorf("GT: %v doesn't give one", z)
+		}
+	}
+
+	// G2 and GT don't reduce k, so Order only gives the identity on the
+	// subgroup, and subgroup checks that multiply by Order still work.
+	outside2 := &G2{twistPointOutsideG2(t, &vectorSource{seed: "ScalarMult outside G2"})}
+	if new(G2).ScalarMult(outside2, Order).p.IsInfinity() {
+		t.Error("G2: Order kills a point outside of G₂")
+	}
+	if !new(G2).ScalarMult(outside2, minusK).Equal(new(G2).Neg(new(G2).ScalarMult(outside2, k))) {
+		t.Error("G2: -k doesn't give the negation outside of G₂")
+	}
+	outsideT := &GT{(&vectorSource{seed: "ScalarMult outside GT"}).nextCyclotomic()}
+	if new(GT).ScalarMult(outsideT, Order).p.IsOne() {
+		t.Error("GT: Order kills an element outside of GT")
+	}
+	inv := new(GT).ScalarMult(outsideT, minusK)
+	if !inv.p.Mul(inv.p, new(GT).ScalarMult(outsideT, k).p).IsOne() {
+		t.Error("GT: -k doesn't give the inverse outside of GT")
+	}
+
+	// ScalarMult doesn't change k.
+	kk := new(big.Int).Set(minusK)
+	new(G1).ScalarMult(a, kk)
+	if kk.Cmp(minusK) != 0 {
+		t.Error("ScalarMult changed its scalar")
+	}
+}
+
+func TestGroupIdentities(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	inf1 := new(G1).ScalarBaseMult(new(big.Int))
+	if !new(G1).Add(a, new(G1).Neg(a)).Equal(inf1) {
+		t.Error("G1: P + (-P) isn't the point at infinity")
+	}
+	if !new(G1).Add(a, inf1).Equal(a) || !new(G1).Add(inf1, a).Equal(a) {
+		t.Error("G1: P + O isn't P")
+	}
+	if !new(G1).Neg(inf1).Equal(inf1) || !new(G1).Double(inf1).Equal(inf1) {
+		t.Error("G1: -O or 2O isn't O")
+	}
+	if !new(G1).Double(a).Equal(new(G1).Add(a, a)) {
+		t.Error("G1: 2P isn't P + P")
+	}
+	if d := new(G1).Set(a); !d.Double(d).Equal(new(G1).ScalarMult(a, big.NewInt(2))) {
+		t.Error("G1: aliased Double doesn't match ScalarMult")
+	}
+
+	_, b, _ := RandomG2(rand.Reader)
+	inf2 := new(G2).ScalarBaseMult(new(big.Int))
+	if !new(G2).Add(b, new(G2).Neg(b)).Equal(inf2) {
+		t.Error("G2: P + (-P) isn't the point at infinity")
+	}
+	if !new(G2).Add(b, inf2).Equal(b) || !new(G2).Add(inf2, b).Equal(b) {
+		t.Error("G2: P + O isn't P")
+	}
+	if !new(G2).Neg(inf2).Equal(inf2) || !new(G2).Double(inf2).Equal(inf2) {
+		t.Error("G2: -O or 2O isn't O")
+	}
+	if !new(G2).Double(b).Equal(new(G2).Add(b, b)) {
+		t.Error("G2: 2P isn't P + P")
+	}
+	if d := new(G2).Set(b); !d.Double(d).Equal(new(G2).ScalarMult(b, big.NewInt(2))) {
+		t.Error("G2: aliased Double doesn't match ScalarMult")
+	}
+
+	_, c, _ := RandomGT(rand.Reader)
+	one := new(GT).ScalarBaseMult(new(big.Int))
+	if !new(GT).Add(c, new(GT).Neg(c)).Equal(one) {
+		t.Error("GT: a·a⁻¹ isn't one")
+	}
+	if !new(GT).Add(c, one).Equal(c) || !new(GT).Add(one, c).Equal(c) {
+		t.Error("GT: a·1 isn't a")
+	}
+	if !new(GT).Neg(one).Equal(one) || !new(GT).Double(one).Equal(one) {
+		t.Error("GT: 1⁻¹ or 1² isn't one")
+	}
+	if !new(GT).Double(c).Equal(new(GT).Add(c, c)) {
+		t.Error("GT: a² isn't a·a")
+	}
+}
+
+func TestRandomDeterministic(t *testing.T) {
+	k1, a1, err := RandomG1(mathrand.New(mathrand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, a2, _ := RandomG1(mathrand.New(mathrand.NewSource(1)))
+	if k1.Cmp(k2) != 0 || !a1.Equal(a2) {
+		t.Fatal("RandomG1 isn't reproducible")
+	}
+	if !a1.Equal(new(G1).ScalarBaseMult(k1)) {
+		t.Fatal("RandomG1 returned a point that doesn't match its scalar")
+	}
+
+	l1, b1, _ := RandomG2(mathrand.New(mathrand.NewSource(2)))
+	l2, b2, _ := RandomG2(mathrand.New(mathrand.NewSource(2)))
+	if l1.Cmp(l2) != 0 || !b1.Equal(b2) {
+		t.Fatal("RandomG2 isn't reproducible")
+	}
+	if !b1.Equal(new(G2).ScalarBaseMult(l1)) {
+		t.Fatal("RandomG2 returned a point that doesn't match its scalar")
+	}
+}
+
+func TestRandomKRejection(t *testing.T) {
+	// Zero, Order and 2²⁵⁶-1 must be rejected, not reduced.
+	ones := bytes.Repeat([]byte{0xff}, ScalarSize)
+	input := append(make([]byte, ScalarSize), Order.FillBytes(make([]byte, ScalarSize))...)
+	input = append(input, ones...)
+	want := new(big.Int).Sub(Order, big.NewInt(1))
+	input = append(input, want.FillBytes(make([]byte, ScalarSize))...)
+
+	k, err := randomK(bytes.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Cmp(want) != 0 {
+		t.Fatalf("got %v, want Order-1", k)
+	}
+
+	if _, err := randomK(bytes.NewReader(ones)); err == nil {
+		t.Fatal("randomK didn't fail when the reader ran out")
+	}
+}
+
+// TestRandomKBias checks the proportion of scalars below 2²⁵⁶ mod Order. If
+// randomK reduced 256-bit values modulo Order instead of rejecting those that
+// are not less than it, these scalars would be twice as likely as the others
+// and make up about 88% of the samples instead of 78%.
+func TestRandomKBias(t *testing.T) {
+	boundary := new(big.Int).Lsh(big.NewInt(1), 8*ScalarSize)
+	boundary.Mod(boundary, Order)
+	want, _ := new(big.Float).Quo(new(big.Float).SetInt(boundary), new(big.Float).SetInt(Order)).Float64()
+
+	const samples = 4000
+	r := mathrand.New(mathrand.NewSource(3))
+	below := 0
+	for i := 0; i < samples; i++ {
+		k, err := randomK(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.Sign() <= 0 || k.Cmp(Order) >= 0 {
+			t.Fatalf("scalar %v out of range", k)
+		}
+		if k.Cmp(boundary) < 0 {
+			below++
+		}
+	}
+	if got := float64(below) / samples; got < want-0.03 || got > want+0.03 {
+		t.Fatalf("%.3f of the scalars are below 2²⁵⁶ mod Order, want %.3f", got, want)
+	}
+}
+
 func TestG1(t *testing.T) {
 	k, Ga, err := RandomG1(rand.Reader)
 	if err != nil {
@@ -78,6 +293,39 @@ func TestG2Marshal(t *testing.T) {
 	}
 }
 
+func TestUnmarshalTrusted(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+	ma, mb := a.Marshal(), b.Marshal()
+
+	a2, b2 := new(G1), new(G2)
+	if _, err := a2.UnmarshalTrusted(ma); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b2.UnmarshalTrusted(mb); err != nil {
+		t.Fatal(err)
+	}
+	if !a2.Equal(a) || !b2.Equal(b) {
+		t.Fatal("points don't round-trip")
+	}
+
+	// Points that aren't on the curve are only caught by Unmarshal.
+	ma[len(ma)-1] ^= 1
+	mb[len(mb)-1] ^= 1
+	if _, err := new(G1).Unmarshal(ma); err == nil {
+		t.Fatal("Unmarshal accepted a G₁ point that isn't on the curve")
+	}
+	if _, err := new(G1).UnmarshalTrusted(ma); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := new(G2).Unmarshal(mb); err == nil {
+		t.Fatal("Unmarshal accepted a G₂ point that isn't on the curve")
+	}
+	if _, err := new(G2).UnmarshalTrusted(mb); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestGT(t *testing.T) {
 	k, Ga, err := RandomGT(rand.Reader)
 	if err != nil {
@@ -97,6 +345,30 @@ func TestGT(t *testing.T) {
 	}
 }
 
+func TestGTScalarBaseMult(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	for _, k := range []*big.Int{k, big.NewInt(0), big.NewInt(1), Order, new(big.Int).Neg(k), new(big.Int).Add(Order, k)} {
+		want := (&gfP12{}).Exp(gfP12Gen, new(big.Int).Mod(k, Order))
+		if got := new(GT).ScalarBaseMult(k); *got.p != *want {
+			t.Fatalf("ScalarBaseMult(%v) doesn't match Exp", k)
+		}
+	}
+}
+
+func TestG1G2ScalarBaseMult(t *testing.T) {
+	for _, k := range ctTestScalars() {
+		reduced := new(big.Int).Mod(k, Order)
+		want1 := new(G1).ScalarMult(&G1{curveGen}, reduced)
+		if got := new(G1).ScalarBaseMult(k); !got.Equal(want1) {
+			t.Fatalf("G1.ScalarBaseMult(%v) doesn't match ScalarMult", k)
+		}
+		want2 := new(G2).ScalarMult(&G2{twistGen}, reduced)
+		if got := new(G2).ScalarBaseMult(k); !got.Equal(want2) {
+			t.Fatalf("G2.ScalarBaseMult(%v) doesn't match ScalarMult", k)
+		}
+	}
+}
+
 func TestGTMarshal(t *testing.T) {
 	_, Ga, err := RandomGT(rand.Reader)
 	if err != nil {
@@ -116,6 +388,55 @@ func TestGTMarshal(t *testing.T) {
 	}
 }
 
+func TestGTCanonical(t *testing.T) {
+	a, _ := rand.Int(rand.Reader, Order)
+	e1 := Pair(new(G1).ScalarBaseMult(a), &G2{twistGen})
+	e2 := Pair(&G1{curveGen}, new(G2).ScalarBaseMult(a))
+	if !bytes.Equal(e1.Canonical(), e2.Canonical()) {
+		t.Fatal("equal elements have different canonical encodings")
+	}
+
+	// ω = (0τ² + 0τ + 1)ω + 0 has its single non-zero coordinate, x₀.1, in
+	// the sixth position.
+	omega := &gfP12{}
+	omega.x.z.y = *newGFp(1)
+	want := make([]byte, 12*32)
+	want[6*32-1] = 1
+	if got := (&GT{omega}).Canonical(); !bytes.Equal(got, want) {
+		t.Fatalf("canonical encoding of ω is %x", got)
+	}
+
+	want[6*32-1] = 0
+	want[12*32-1] = 1
+	if got := (&GT{(&gfP12{}).SetOne()}).Canonical(); !bytes.Equal(got, want) {
+		t.Fatalf("canonical encoding of one is %x", got)
+	}
+}
+
+func TestGTEqual(t *testing.T) {
+	a, _ := rand.Int(rand.Reader, Order)
+	e1 := Pair(new(G1).ScalarBaseMult(a), &G2{twistGen})
+	e2 := Pair(&G1{curveGen}, new(G2).ScalarBaseMult(a))
+	if e1 == e2 || *e1 == *e2 {
+		t.Fatal("separately computed elements compare equal with ==")
+	}
+	if !e1.Equal(e2) || !e2.Equal(e1) {
+		t.Fatal("equal elements are not Equal")
+	}
+	if !e1.Equal(new(GT).Set(e1)) {
+		t.Fatal("copy is not Equal to the original")
+	}
+
+	e3 := new(GT).Add(e1, e1)
+	if e1.Equal(e3) {
+		t.Fatal("different elements are Equal")
+	}
+	one := &GT{(&gfP12{}).SetOne()}
+	if e1.Equal(one) || !new(GT).Add(new(GT).Neg(e1), e1).Equal(one) {
+		t.Fatal("Equal is wrong for the identity")
+	}
+}
+
 func TestBilinearity(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		a, p1, _ := RandomG1(rand.Reader)
@@ -132,6 +453,455 @@ func TestBilinearity(t *testing.T) {
 	}
 }
 
+func TestCheckBilinear(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, q2, _ := RandomG2(rand.Reader)
+	inf1 := new(G1).ScalarBaseMult(new(big.Int))
+	inf2 := new(G2).ScalarBaseMult(new(big.Int))
+
+	for i := 0; i < 2; i++ {
+		a, _ := rand.Int(rand.Reader, Order)
+		b, _ := rand.Int(rand.Reader, Order)
+		if !CheckBilinear(a, b, p1, q2) {
+			t.Fatalf("CheckBilinear(%v, %v) failed", a, b)
+		}
+
+		// A wrong exponent on the right-hand side is caught.
+		ab := new(big.Int).Mul(a, b)
+		if checkBilinear(a, b, ab.Add(ab, big.NewInt(1)), p1, q2) {
+			t.Fatal("checkBilinear accepted a·b+1 as the exponent")
+		}
+	}
+
+	zero, k := new(big.Int), big.NewInt(-7)
+	for _, tc := range []struct {
+		a, b *big.Int
+		p    *G1
+		q    *G2
+	}{
+		{zero, k, p1, q2},
+		{k, zero, p1, q2},
+		{zero, zero, p1, q2},
+		{k, k, inf1, q2},
+		{k, k, p1, inf2},
+		{new(big.Int).Add(Order, big.NewInt(3)), k, p1, q2},
+	} {
+		if !CheckBilinear(tc.a, tc.b, tc.p, tc.q) {
+			t.Fatalf("CheckBilinear(%v, %v) failed", tc.a, tc.b)
+		}
+	}
+}
+
+func TestPairInverse(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+
+	e := Pair(p1, p2)
+	e.Add(e, PairInverse(p1, p2))
+	if !e.p.IsOne() {
+		t.Fatal("e(a, b)·e(a, b)⁻¹ != 1")
+	}
+
+	want := new(GT).Neg(Pair(p1, p2))
+	if *PairInverse(p1, p2).p != *want.p {
+		t.Fatal("PairInverse doesn't match the conjugated pairing")
+	}
+}
+
+func TestPairExp(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+	k, _ := rand.Int(rand.Reader, Order)
+
+	for _, k := range []*big.Int{k, big.NewInt(0), big.NewInt(1), new(big.Int).Neg(k), new(big.Int).Add(k, Order)} {
+		want := new(GT).ScalarMult(Pair(p1, p2), new(big.Int).Mod(k, Order))
+		if got := PairExp(p1, p2, k); !got.Equal(want) {
+			t.Fatalf("PairExp(%v) doesn't match", k)
+		}
+		if got := PairExpG1(p1, p2, k); !got.Equal(want) {
+			t.Fatalf("PairExpG1(%v) doesn't match", k)
+		}
+	}
+}
+
+func TestPairingProduct(t *testing.T) {
+	const n = 4
+	a, b := make([]*G1, n), make([]*G2, n)
+	want := &GT{(&gfP12{}).SetOne()}
+	for i := range a {
+		_, a[i], _ = RandomG1(rand.Reader)
+		_, b[i], _ = RandomG2(rand.Reader)
+		want.Add(want, Pair(a[i], b[i]))
+	}
+	if got := PairingProduct(a, b); !got.Equal(want) {
+		t.Fatal("PairingProduct doesn't match the product of pairings")
+	}
+	if got := PairingProduct(a[:1], b[:1]); !got.Equal(Pair(a[0], b[0])) {
+		t.Fatal("PairingProduct of one pair doesn't match Pair")
+	}
+	if got := PairingProduct(nil, nil); !got.p.IsOne() {
+		t.Fatal("empty PairingProduct isn't one")
+	}
+
+	// Nil points and points at infinity contribute one.
+	want = Pair(a[0], b[0])
+	inf1, inf2 := new(G1).ScalarBaseMult(Order), new(G2).ScalarBaseMult(Order)
+	got := PairingProduct([]*G1{a[0], nil, inf1, a[3], new(G1)}, []*G2{b[0], b[1], b[2], inf2, b[0]})
+	if !got.Equal(want) {
+		t.Fatal("nil points or points at infinity don't contribute one")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("mismatched lengths didn't panic")
+			}
+		}()
+		PairingProduct(a, b[:1])
+	}()
+}
+
+func TestPairingProductParallel(t *testing.T) {
+	// Force the parallel path whatever the number of CPUs.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	const n = 4*multiMillerChunk + 3
+	a, b := make([]*G1, n), make([]*G2, n)
+	for i := range a {
+		_, a[i], _ = RandomG1(rand.Reader)
+		_, b[i], _ = RandomG2(rand.Reader)
+	}
+	// The same point may appear in several chunks, and some pairs are at
+	// infinity.
+	a[n-1], b[1] = a[0], b[0]
+	a[2] = new(G1).ScalarBaseMult(new(big.Int))
+
+	ps, qs := pairingInputs(a, b)
+	if got, want := multiMiller(qs, ps), multiMillerSerial(qs, ps); *got != *want {
+		t.Fatal("the parallel Miller loops don't match the serial ones")
+	}
+
+	want := &GT{(&gfP12{}).SetOne()}
+	for i := range a {
+		want.Add(want, Pair(a[i], b[i]))
+	}
+	if got := PairingProduct(a, b); !got.Equal(want) {
+		t.Fatal("PairingProduct doesn't match the product of pairings")
+	}
+}
+
+func TestPairingCheck(t *testing.T) {
+	// e(a·g₁, b·g₂) · e(-ab·g₁, g₂) = 1.
+	a, _ := rand.Int(rand.Reader, Order)
+	b, _ := rand.Int(rand.Reader, Order)
+	ab := new(big.Int).Mul(a, b)
+	ab.Neg(ab).Mod(ab, Order)
+
+	g1s := []*G1{new(G1).ScalarBaseMult(a), new(G1).ScalarBaseMult(ab)}
+	g2s := []*G2{new(G2).ScalarBaseMult(b), Gen2()}
+	if !PairingCheck(g1s, g2s) {
+		t.Fatal("valid relation rejected")
+	}
+
+	g1s[1].Add(g1s[1], Gen1())
+	if PairingCheck(g1s, g2s) {
+		t.Fatal("tampered relation accepted")
+	}
+	if PairingCheck(g1s[:1], g2s[:1]) {
+		t.Fatal("single non-trivial pairing accepted")
+	}
+
+	if !PairingCheck(nil, nil) {
+		t.Fatal("empty product isn't one")
+	}
+	if !PairingCheck([]*G1{nil, new(G1).ScalarBaseMult(Order)}, []*G2{Gen2(), Gen2()}) {
+		t.Fatal("nil points or points at infinity don't contribute one")
+	}
+}
+
+func TestVerifyPairingProduct(t *testing.T) {
+	// A Groth16-shaped check: e(A, B) = e(α, β)·e(L, γ)·e(C, δ) with
+	// A = a·g₁, B = b·g₂, α = g₁, β = g₂, L = l·g₁, γ = g₂, C = c·g₁ and
+	// δ = d·g₂, which holds for ab = 1 + l + cd.
+	l, _ := rand.Int(rand.Reader, Order)
+	c, _ := rand.Int(rand.Reader, Order)
+	d, _ := rand.Int(rand.Reader, Order)
+	a, _ := rand.Int(rand.Reader, Order)
+	ab := new(big.Int).Mul(c, d)
+	ab.Add(ab, l).Add(ab, big.NewInt(1)).Mod(ab, Order)
+	b := new(big.Int).ModInverse(a, Order)
+	b.Mul(b, ab).Mod(b, Order)
+
+	A, B := new(G1).ScalarBaseMult(a), new(G2).ScalarBaseMult(b)
+	minusL := new(G1).Neg(new(G1).ScalarBaseMult(l))
+	minusC := new(G1).Neg(new(G1).ScalarBaseMult(c))
+	delta := new(G2).ScalarBaseMult(d)
+	alphaBeta := Pair(Gen1(), Gen2())
+
+	terms := []PairingTerm{{A, B}, {minusL, Gen2()}, {minusC, delta}}
+	if !VerifyPairingProduct(terms, alphaBeta) {
+		t.Fatal("valid product rejected")
+	}
+	if VerifyPairingProduct(terms, nil) {
+		t.Fatal("product isn't one but was accepted against a nil target")
+	}
+
+	terms[2].G1 = new(G1).Neg(minusC)
+	if VerifyPairingProduct(terms, alphaBeta) {
+		t.Fatal("product with a missing negation accepted")
+	}
+
+	if !VerifyPairingProduct(nil, nil) || !VerifyPairingProduct(nil, &GT{(&gfP12{}).SetOne()}) {
+		t.Fatal("empty product isn't one")
+	}
+	if VerifyPairingProduct(nil, alphaBeta) {
+		t.Fatal("empty product equals e(g₁, g₂)")
+	}
+}
+
+func TestPairScalars(t *testing.T) {
+	a, _ := rand.Int(rand.Reader, Order)
+	b, _ := rand.Int(rand.Reader, Order)
+
+	want := Pair(new(G1).ScalarBaseMult(a), new(G2).ScalarBaseMult(b))
+	if got := PairScalars(a, b); *got.p != *want.p {
+		t.Fatal("PairScalars(a, b) != e(a·g₁, b·g₂)")
+	}
+	if !PairScalars(a, big.NewInt(0)).p.IsOne() {
+		t.Fatal("PairScalars(a, 0) != 1")
+	}
+}
+
+func TestFinalExponentiationParts(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+	_, q1, _ := RandomG1(rand.Reader)
+	_, q2, _ := RandomG2(rand.Reader)
+
+	a, b := Miller(p1, p2), Miller(q1, q2)
+	want := new(GT).Set(a)
+	want.Finalize()
+	if got := HardPart(EasyPart(a)); *got.p != *want.p {
+		t.Fatal("HardPart(EasyPart(x)) != x.Finalize()")
+	}
+
+	// The easy part is a homomorphism.
+	ab := new(GT).Add(a, b)
+	got := new(GT).Add(EasyPart(a), EasyPart(b))
+	if *EasyPart(ab).p != *got.p {
+		t.Fatal("EasyPart(a·b) != EasyPart(a)·EasyPart(b)")
+	}
+	want.Add(Pair(p1, p2), Pair(q1, q2))
+	if *HardPart(got).p != *want.p {
+		t.Fatal("HardPart(EasyPart(a)·EasyPart(b)) != e(p1, p2)·e(q1, q2)")
+	}
+}
+
+func TestCyclotomicProjection(t *testing.T) {
+	src := &vectorSource{seed: "cyclotomic projection"}
+	p2 := new(big.Int).Mul(p, p)
+	phi := new(big.Int).Mul(p2, p2)
+	phi.Sub(phi, p2).Add(phi, big.NewInt(1))
+
+	for i := 0; i < 4; i++ {
+		a := &GT{src.nextGFp12()}
+		got := new(GT).CyclotomicProjection(a)
+		if *got.p != *EasyPart(a).p {
+			t.Fatal("CyclotomicProjection doesn't match EasyPart")
+		}
+
+		// a^(p⁶) = a⁻¹, where the p⁶-power Frobenius map is the conjugation.
+		conj := (&gfP12{}).Conjugate(got.p)
+		inv := (&gfP12{}).Invert(got.p)
+		if *conj != *inv {
+			t.Fatal("projected element doesn't satisfy a^(p⁶) = a⁻¹")
+		}
+		if !(&gfP12{}).Exp(got.p, phi).IsOne() {
+			t.Fatal("projected element doesn't have order dividing p⁴-p²+1")
+		}
+
+		// On the cyclotomic subgroup the projection is exponentiation by
+		// -2(p²+1).
+		k := new(big.Int).Add(p2, big.NewInt(1))
+		k.Lsh(k, 1).Neg(k).Mod(k, phi)
+		again := new(GT).CyclotomicProjection(got)
+		if want := (&gfP12{}).Exp(got.p, k); *again.p != *want {
+			t.Fatal("projection of a cyclotomic element isn't its -2(p²+1)-th power")
+		}
+
+		// The result may alias the input.
+		if a.CyclotomicProjection(a); *a.p != *got.p {
+			t.Fatal("aliased CyclotomicProjection mismatch")
+		}
+	}
+
+	if !new(GT).CyclotomicProjection(&GT{&gfP12{}}).p.IsZero() {
+		t.Fatal("projection of zero isn't zero")
+	}
+}
+
+// hardPartExponent is (p⁴-p²+1)/Order, the exponent of the hard part of the
+// final exponentiation.
+func hardPartExponent() *big.Int {
+	p2 := new(big.Int).Mul(p, p)
+	e := new(big.Int).Mul(p2, p2)
+	e.Sub(e, p2).Add(e, big.NewInt(1))
+	if new(big.Int).Mod(e, Order).Sign() != 0 {
+		panic("Order doesn't divide p⁴-p²+1")
+	}
+	return e.Div(e, Order)
+}
+
+func TestFinalExponentiationHardPart(t *testing.T) {
+	exp := hardPartExponent()
+	for i := 0; i < 4; i++ {
+		_, a, _ := RandomG1(rand.Reader)
+		_, b, _ := RandomG2(rand.Reader)
+		f := finalExponentiationEasyPart(miller(b.p, a.p))
+
+		want := (&gfP12{}).Exp(f, exp)
+		if got := finalExponentiationHardPart(f); *got != *want {
+			t.Fatal("the hard part doesn't match the generic exponentiation")
+		}
+	}
+}
+
+// BenchmarkFinalExponentiationHardPart compares the addition chain of the
+// hard part with generic exponentiations by (p⁴-p²+1)/Order.
+func BenchmarkFinalExponentiationHardPart(b *testing.B) {
+	exp := hardPartExponent()
+	f := finalExponentiationEasyPart(miller(twistGen, curveGen))
+	out := &gfP12{}
+
+	b.Run("Chain", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			finalExponentiationHardPart(f)
+		}
+	})
+	b.Run("ExpCyclo6", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			out.ExpCyclo6(f, exp)
+		}
+	})
+	b.Run("Exp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			out.Exp(f, exp)
+		}
+	})
+}
+
+func TestGTSqrt(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		_, g1, _ := RandomG1(rand.Reader)
+		_, g2, _ := RandomG2(rand.Reader)
+		a := Pair(g1, g2)
+		root := new(GT).Sqrt(a)
+		if !new(GT).Add(root, root).Equal(a) {
+			t.Fatal("Sqrt(a)² != a")
+		}
+		if !root.IsValid() {
+			t.Fatal("Sqrt(a) isn't in GT")
+		}
+	}
+
+	// The root is unique: the square root of a² is a, not -a.
+	_, a, _ := RandomGT(rand.Reader)
+	if got := new(GT).Sqrt(new(GT).Double(a)); !got.Equal(a) {
+		t.Fatal("Sqrt(a²) != a")
+	}
+	one := new(GT).ScalarBaseMult(new(big.Int))
+	if !new(GT).Sqrt(one).Equal(one) {
+		t.Fatal("Sqrt(1) != 1")
+	}
+	if b := new(GT).Set(a); !b.Sqrt(b).Double(b).Equal(a) {
+		t.Fatal("aliased Sqrt mismatch")
+	}
+
+	for name, bad := range map[string]*GT{
+		"cyclotomic": {(&vectorSource{seed: "GT Sqrt"}).nextCyclotomic()},
+		"Miller":     Miller(&G1{curveGen}, &G2{twistGen}),
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Sqrt didn't panic on a %s element outside of GT", name)
+				}
+			}()
+			new(GT).Sqrt(bad)
+		}()
+	}
+}
+
+func TestGTExpU64(t *testing.T) {
+	_, a, _ := RandomGT(rand.Reader)
+
+	for _, k := range []uint64{0, 1, 2, 3, 7, 255, 256, 1<<32 + 1, 1<<63 - 1, 1<<64 - 1} {
+		want := new(GT).ScalarMult(a, new(big.Int).SetUint64(k))
+		if got := new(GT).ExpU64(a, k); *got.p != *want.p {
+			t.Fatalf("ExpU64(a, %d) doesn't match ScalarMult", k)
+		}
+	}
+
+	out := new(GT).Set(a)
+	allocs := testing.AllocsPerRun(10, func() {
+		out.ExpU64(a, 0xdeadbeef)
+	})
+	if allocs != 0 {
+		t.Fatalf("ExpU64 allocated %v times", allocs)
+	}
+}
+
+func TestMillerLoop(t *testing.T) {
+	var a [3]*G1
+	var b [3]*G2
+	for i := range a {
+		_, a[i], _ = RandomG1(rand.Reader)
+		_, b[i], _ = RandomG2(rand.Reader)
+	}
+
+	m := MillerLoop(a[0], b[0])
+	before := *m.p
+	if got := m.FinalExponentiation(); !got.Equal(Pair(a[0], b[0])) {
+		t.Fatal("FinalExponentiation(MillerLoop) doesn't match Pair")
+	}
+	if *m.p != before {
+		t.Fatal("FinalExponentiation modified the Miller loop output")
+	}
+
+	// The Miller loop output itself is generally not in GT.
+	if m.IsInSubGroup() {
+		t.Fatal("bare Miller loop output is in GT")
+	}
+
+	want := new(GT).Set(Pair(a[0], b[0]))
+	for i := 1; i < len(a); i++ {
+		m.Add(m, MillerLoop(a[i], b[i]))
+		want.Add(want, Pair(a[i], b[i]))
+	}
+	if !m.FinalExponentiation().Equal(want) {
+		t.Fatal("final exponentiation of the product of Miller loops doesn't match the product of pairings")
+	}
+	if !m.FinalExponentiation().Equal(PairingProduct(a[:], b[:])) {
+		t.Fatal("final exponentiation of the product of Miller loops doesn't match PairingProduct")
+	}
+}
+
+func TestGTExp(t *testing.T) {
+	_, a, _ := RandomGT(rand.Reader)
+	k, _ := rand.Int(rand.Reader, Order)
+
+	want := new(GT).ScalarMult(a, k)
+	if got := new(GT).Exp(a, k); !got.Equal(want) {
+		t.Fatal("Exp doesn't match ScalarMult")
+	}
+	if got := new(GT).Exp(a, new(big.Int).Neg(k)); !got.Equal(new(GT).Neg(want)) {
+		t.Fatal("Exp with a negated exponent doesn't give the inverse")
+	}
+	if got := new(GT).Set(a); !got.Exp(got, k).Equal(want) {
+		t.Fatal("aliased Exp doesn't match ScalarMult")
+	}
+}
+
 func TestTripartiteDiffieHellman(t *testing.T) {
 	a, _ := rand.Int(rand.Reader, Order)
 	b, _ := rand.Int(rand.Reader, Order)
@@ -170,7 +940,7 @@ func TestSelfAddG1(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	Gb := &G1{curveGen}
+	Gb := &G1{&curvePoint{}}
 	Gb.p.Double(Ga.p)
 	mb := Gb.Marshal()
 
@@ -188,7 +958,7 @@ func TestSelfAddG2(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	Gb := &G2{twistGen}
+	Gb := &G2{&twistPoint{}}
 	Gb.p.Double(Ga.p)
 	mb := Gb.Marshal()
 
@@ -200,6 +970,98 @@ func TestSelfAddG2(t *testing.T) {
 	}
 }
 
+func TestG1EqualProjective(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG1(rand.Reader)
+	a.p.MakeAffine()
+
+	// Scale the Jacobian coordinates of a by λ, which doesn't change the point.
+	lambda := newGFp(7)
+	l2, l3 := &gfP{}, &gfP{}
+	gfpMul(l2, lambda, lambda)
+	gfpMul(l3, l2, lambda)
+	scaled := &G1{&curvePoint{}}
+	gfpMul(&scaled.p.x, &a.p.x, l2)
+	gfpMul(&scaled.p.y, &a.p.y, l3)
+	gfpMul(&scaled.p.z, &a.p.z, lambda)
+	gfpMul(&scaled.p.t, &scaled.p.z, &scaled.p.z)
+
+	if *scaled.p == *a.p {
+		t.Fatal("scaling didn't change the representation")
+	}
+	if !a.Equal(scaled) || !scaled.Equal(a) {
+		t.Fatal("equal points in different representations are not equal")
+	}
+	if a.Equal(b) {
+		t.Fatal("different points are equal")
+	}
+	if a.Equal(new(G1).Neg(a)) {
+		t.Fatal("a point is equal to its negation")
+	}
+
+	double := new(G1).Add(a, a)
+	if !double.Equal(new(G1).ScalarMult(scaled, big.NewInt(2))) {
+		t.Fatal("a+a != 2·a")
+	}
+
+	inf := new(G1).ScalarBaseMult(big.NewInt(0))
+	infScaled := new(G1).Add(a, new(G1).Neg(scaled))
+	if !inf.Equal(infScaled) || inf.Equal(a) || a.Equal(inf) {
+		t.Fatal("bad comparison with the point at infinity")
+	}
+
+	// Any point with z = 0 is the point at infinity, whatever x and y.
+	dirtyInf := &G1{&curvePoint{x: a.p.x, y: a.p.y}}
+	if !dirtyInf.Equal(inf) || !inf.Equal(dirtyInf) || dirtyInf.Equal(a) || a.Equal(dirtyInf) {
+		t.Fatal("bad comparison with a non-canonical point at infinity")
+	}
+}
+
+func TestG2EqualProjective(t *testing.T) {
+	_, a, _ := RandomG2(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+	a.p.MakeAffine()
+
+	lambda := &gfP2{*newGFp(3), *newGFp(5)}
+	l2 := (&gfP2{}).Square(lambda)
+	l3 := (&gfP2{}).Mul(l2, lambda)
+	scaled := &G2{&twistPoint{}}
+	scaled.p.x.Mul(&a.p.x, l2)
+	scaled.p.y.Mul(&a.p.y, l3)
+	scaled.p.z.Mul(&a.p.z, lambda)
+	scaled.p.t.Square(&scaled.p.z)
+
+	if *scaled.p == *a.p {
+		t.Fatal("scaling didn't change the representation")
+	}
+	if !a.Equal(scaled) || !scaled.Equal(a) {
+		t.Fatal("equal points in different representations are not equal")
+	}
+	if a.Equal(b) {
+		t.Fatal("different points are equal")
+	}
+	if a.Equal(new(G2).Neg(a)) {
+		t.Fatal("a point is equal to its negation")
+	}
+
+	double := new(G2).Add(a, a)
+	if !double.Equal(new(G2).ScalarMult(scaled, big.NewInt(2))) {
+		t.Fatal("a+a != 2·a")
+	}
+
+	inf := new(G2).ScalarBaseMult(big.NewInt(0))
+	infScaled := new(G2).Add(a, new(G2).Neg(scaled))
+	if !inf.Equal(infScaled) || inf.Equal(a) || a.Equal(inf) {
+		t.Fatal("bad comparison with the point at infinity")
+	}
+
+	// Any point with z = 0 is the point at infinity, whatever x and y.
+	dirtyInf := &G2{&twistPoint{x: a.p.x, y: a.p.y}}
+	if !dirtyInf.Equal(inf) || !inf.Equal(dirtyInf) || dirtyInf.Equal(a) || a.Equal(dirtyInf) {
+		t.Fatal("bad comparison with a non-canonical point at infinity")
+	}
+}
+
 func TestDirtyUnmarshal(t *testing.T) {
 	_, Ga, err := RandomG2(rand.Reader)
 	if err != nil {
@@ -212,6 +1074,286 @@ func TestDirtyUnmarshal(t *testing.T) {
 	}
 }
 
+// mulFp2 returns (a0 + a1·i)(b0 + b1·i) mod p, with i² = -1.
+func mulFp2(a0, a1, b0, b1 *big.Int) (c0, c1 *big.Int) {
+	c0 = new(big.Int).Mul(a0, b0)
+	c0.Sub(c0, new(big.Int).Mul(a1, b1)).Mod(c0, p)
+	c1 = new(big.Int).Mul(a0, b1)
+	c1.Add(c1, new(big.Int).Mul(a1, b0)).Mod(c1, p)
+	return c0, c1
+}
+
+func TestXY(t *testing.T) {
+	coord := func(v *big.Int) []byte { return v.FillBytes(make([]byte, 32)) }
+	for i := 0; i < 4; i++ {
+		_, a, _ := RandomG1(rand.Reader)
+		// Leave the point in Jacobian form.
+		a.Add(a, &G1{curveGen})
+		x, y := a.XY()
+
+		// y² = x³ + 3.
+		lhs := new(big.Int).Mul(y, y)
+		rhs := new(big.Int).Exp(x, big.NewInt(3), nil)
+		rhs.Add(rhs, big.NewInt(3))
+		if lhs.Mod(lhs, p).Cmp(rhs.Mod(rhs, p)) != 0 || x.Cmp(p) >= 0 || y.Cmp(p) >= 0 {
+			t.Fatal("G1 coordinates aren't on the curve")
+		}
+		a2 := new(G1)
+		if _, err := a2.Unmarshal(append(coord(x), coord(y)...)); err != nil || !a2.Equal(a) {
+			t.Fatalf("G1 coordinates don't reconstruct the point: %v", err)
+		}
+
+		_, b, _ := RandomG2(rand.Reader)
+		b.Add(b, &G2{twistGen})
+		x0, x1, y0, y1 := b.XY()
+
+		// y² = x³ + 3/ξ.
+		l0, l1 := mulFp2(y0, y1, y0, y1)
+		r0, r1 := mulFp2(x0, x1, x0, x1)
+		r0, r1 = mulFp2(r0, r1, x0, x1)
+		r0.Add(r0, twistB.y.bigInt()).Mod(r0, p)
+		r1.Add(r1, twistB.x.bigInt()).Mod(r1, p)
+		if l0.Cmp(r0) != 0 || l1.Cmp(r1) != 0 {
+			t.Fatal("G2 coordinates aren't on the twist")
+		}
+		m := append([]byte{0x01}, coord(x1)...)
+		m = append(append(append(m, coord(x0)...), coord(y1)...), coord(y0)...)
+		b2 := new(G2)
+		if _, err := b2.Unmarshal(m); err != nil || !b2.Equal(b) {
+			t.Fatalf("G2 coordinates don't reconstruct the point: %v", err)
+		}
+	}
+
+	x, y := new(G1).ScalarBaseMult(new(big.Int)).XY()
+	x0, x1, y0, y1 := new(G2).ScalarBaseMult(new(big.Int)).XY()
+	for _, v := range []*big.Int{x, y, x0, x1, y0, y1} {
+		if v.Sign() != 0 {
+			t.Fatal("the point at infinity doesn't have zero coordinates")
+		}
+	}
+}
+
+// unreduced returns a + p as raw limbs if that fits in 256 bits, a
+// representation of the same field element that isn't fully reduced, and a
+// itself otherwise.
+func unreduced(a *gfP) gfP {
+	var b gfP
+	var carry uint64
+	for i := range b {
+		b[i], carry = bits.Add64(a[i], p2[i], carry)
+	}
+	if carry != 0 {
+		return *a
+	}
+	return b
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	src := &vectorSource{seed: "canonical Marshal"}
+	pBig := p.FillBytes(make([]byte, 32))
+	checkReduced := func(m []byte) {
+		t.Helper()
+		for i := len(m) % 32; i+32 <= len(m); i += 32 {
+			if bytes.Compare(m[i:i+32], pBig) >= 0 {
+				t.Fatalf("coordinate at offset %d is not less than p", i)
+			}
+		}
+	}
+
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	for i := 0; i < 8; i++ {
+		// Scale the Jacobian coordinates by a random λ: (λ²x, λ³y, λz), with
+		// t = z², and leave x unreduced where possible.
+		l := src.next()
+		l2, l3 := &gfP{}, &gfP{}
+		gfpMul(l2, l, l)
+		gfpMul(l3, l2, l)
+		a := &G1{&curvePoint{}}
+		a.p.Set(g1.p)
+		a.p.MakeAffine()
+		gfpMul(&a.p.x, &a.p.x, l2)
+		a.p.x = unreduced(&a.p.x)
+		gfpMul(&a.p.y, &a.p.y, l3)
+		gfpMul(&a.p.z, &a.p.z, l)
+		gfpMul(&a.p.t, &a.p.z, &a.p.z)
+
+		want := g1.Marshal()
+		if got := a.Marshal(); !bytes.Equal(got, want) {
+			t.Fatal("two representations of a G₁ point marshal differently")
+		}
+		checkReduced(want)
+
+		m := (&gfP2{}).SetZero()
+		m.y.Set(l)
+		m2, m3 := (&gfP2{}).Square(m), &gfP2{}
+		m3.Mul(m2, m)
+		b := &G2{&twistPoint{}}
+		b.p.Set(g2.p)
+		b.p.MakeAffine()
+		b.p.x.Mul(&b.p.x, m2)
+		b.p.y.Mul(&b.p.y, m3)
+		b.p.y.x = unreduced(&b.p.y.x)
+		b.p.z.Mul(&b.p.z, m)
+		b.p.t.Square(&b.p.z)
+
+		want = g2.Marshal()
+		if got := b.Marshal(); !bytes.Equal(got, want) {
+			t.Fatal("two representations of a G₂ point marshal differently")
+		}
+		checkReduced(want)
+
+		// Any point with z = 0 is the point at infinity, whatever x and y.
+		inf1 := &G1{&curvePoint{x: *src.next(), y: *src.next()}}
+		if got := inf1.Marshal(); !bytes.Equal(got, make([]byte, g1Size)) {
+			t.Fatalf("G₁ point at infinity marshals as %x", got)
+		}
+		inf2 := &G2{&twistPoint{x: *src.nextGFp2(), y: *src.nextGFp2()}}
+		if got := inf2.Marshal(); !bytes.Equal(got, []byte{0x00}) {
+			t.Fatalf("G₂ point at infinity marshals as %x", got)
+		}
+	}
+}
+
+// addP adds p to the 32-byte big-endian value at m[off:] and reports whether
+// the sum still fits in 32 bytes.
+func addP(m []byte, off int) bool {
+	v := new(big.Int).SetBytes(m[off : off+32])
+	v.Add(v, p)
+	if v.BitLen() > 256 {
+		return false
+	}
+	v.FillBytes(m[off : off+32])
+	return true
+}
+
+func TestUnmarshalNotReduced(t *testing.T) {
+	// (1 + p, 2) is the generator once reduced.
+	m1 := (&G1{curveGen}).Marshal()
+	if !addP(m1, 0) {
+		t.Fatal("1 + p doesn't fit in 32 bytes")
+	}
+	// So is (p, 0) the point at infinity.
+	inf1 := make([]byte, g1Size)
+	addP(inf1, 0)
+
+	m2 := (&G2{twistGen}).Marshal()
+	for i := 0; !addP(m2, 1+32*i); i++ {
+		if i == 3 {
+			t.Fatal("no coordinate of the G₂ generator fits in 32 bytes plus p")
+		}
+	}
+
+	mt := (&GT{gfP12Gen}).Marshal()
+	for i := 0; !addP(mt, 32*i); i++ {
+		if i == 11 {
+			t.Fatal("no coordinate of the GT generator fits in 32 bytes plus p")
+		}
+	}
+
+	g := new(G1).ScalarBaseMult(big.NewInt(5))
+	for _, m := range [][]byte{m1, inf1} {
+		if _, err := g.Unmarshal(m); err == nil {
+			t.Fatal("G1.Unmarshal accepted a coordinate not less than p")
+		}
+	}
+	if !g.Equal(new(G1).ScalarBaseMult(big.NewInt(5))) {
+		t.Fatal("G1.Unmarshal changed e on error")
+	}
+	if _, err := new(G2).Unmarshal(m2); err == nil {
+		t.Fatal("G2.Unmarshal accepted a coordinate not less than p")
+	}
+	if _, err := new(GT).Unmarshal(mt); err == nil {
+		t.Fatal("GT.Unmarshal accepted a coordinate not less than p")
+	}
+}
+
+func TestUnmarshalConcatenated(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+	_, c, _ := RandomGT(rand.Reader)
+	inf2 := new(G2).ScalarBaseMult(new(big.Int))
+
+	var m []byte
+	m = append(m, a.Marshal()...)
+	m = append(m, inf2.Marshal()...)
+	m = append(m, b.Marshal()...)
+	m = append(m, c.Marshal()...)
+
+	a2, inf22, b2, c2 := new(G1), new(G2), new(G2), new(GT)
+	rest, err := a2.Unmarshal(m)
+	if err == nil {
+		rest, err = inf22.Unmarshal(rest)
+	}
+	if err == nil {
+		rest, err = b2.Unmarshal(rest)
+	}
+	if err == nil {
+		rest, err = c2.Unmarshal(rest)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("%d bytes left over", len(rest))
+	}
+	if !a2.Equal(a) || !inf22.Equal(inf2) || !b2.Equal(b) || !c2.Equal(c) {
+		t.Fatal("values don't round-trip")
+	}
+}
+
+// TestUnmarshalTruncated checks that every proper prefix of a valid encoding
+// is rejected.
+func TestUnmarshalTruncated(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+	_, c, _ := RandomGT(rand.Reader)
+	ma, mb, mc := a.Marshal(), b.Marshal(), c.Marshal()
+
+	for n := 0; n < len(ma); n++ {
+		if _, err := new(G1).Unmarshal(ma[:n]); err == nil {
+			t.Fatalf("G1.Unmarshal accepted %d bytes", n)
+		}
+	}
+	for n := 0; n < len(mb); n++ {
+		if _, err := new(G2).Unmarshal(mb[:n]); err == nil {
+			t.Fatalf("G2.Unmarshal accepted %d bytes", n)
+		}
+	}
+	for n := 0; n < len(mc); n++ {
+		if _, err := new(GT).Unmarshal(mc[:n]); err == nil {
+			t.Fatalf("GT.Unmarshal accepted %d bytes", n)
+		}
+	}
+}
+
+// TestUnmarshalRandom feeds random inputs of every length up to one element
+// of GT to the Unmarshal functions, which must return and not panic. The
+// inputs either start with the byte that marks a point of G₂ or are entirely
+// random.
+func TestUnmarshalRandom(t *testing.T) {
+	buf := make([]byte, gtSize+1)
+	for n := 0; n <= len(buf); n++ {
+		m := buf[:n]
+		for i := 0; i < 4; i++ {
+			rand.Read(m)
+			if n > 0 && i%2 == 0 {
+				m[0] = 0x01
+			}
+			_, err1 := new(G1).Unmarshal(m)
+			new(G1).UnmarshalTrusted(m)
+			new(G2).Unmarshal(m)
+			new(G2).UnmarshalTrusted(m)
+			new(GT).Unmarshal(m)
+			if n >= g1Size && err1 == nil {
+				// A random pair of coordinates is on the curve with
+				// negligible probability.
+				t.Fatalf("G1.Unmarshal accepted %x", m)
+			}
+		}
+	}
+}
+
 func BenchmarkG1(b *testing.B) {
 	x, _ := rand.Int(rand.Reader, Order)
 	b.ResetTimer()
@@ -230,6 +1372,37 @@ func BenchmarkG2(b *testing.B) {
 	}
 }
 
+// BenchmarkScalarBaseMult compares ScalarBaseMult, which uses the generator
+// tables, with ScalarMult on the generator.
+func BenchmarkScalarBaseMult(b *testing.B) {
+	x, _ := rand.Int(rand.Reader, Order)
+	PrecomputeG1Generator()
+	PrecomputeG2Generator()
+	e1, g1 := new(G1), &G1{curveGen}
+	e2, g2 := new(G2), &G2{twistGen}
+
+	b.Run("G1/ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e1.ScalarMult(g1, x)
+		}
+	})
+	b.Run("G1/ScalarBaseMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e1.ScalarBaseMult(x)
+		}
+	})
+	b.Run("G2/ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e2.ScalarMult(g2, x)
+		}
+	})
+	b.Run("G2/ScalarBaseMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e2.ScalarBaseMult(x)
+		}
+	})
+}
+
 func BenchmarkGT(b *testing.B) {
 	x, _ := rand.Int(rand.Reader, Order)
 	b.ResetTimer()
@@ -239,8 +1412,83 @@ func BenchmarkGT(b *testing.B) {
 	}
 }
 
+func BenchmarkGTScalarMult(b *testing.B) {
+	x, _ := rand.Int(rand.Reader, Order)
+	g := &GT{gfP12Gen}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		new(GT).ScalarMult(g, x)
+	}
+}
+
 func BenchmarkPairing(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		Pair(&G1{curveGen}, &G2{twistGen})
 	}
 }
+
+func BenchmarkPairingProduct(b *testing.B) {
+	const n = 4
+	g1s, g2s := make([]*G1, n), make([]*G2, n)
+	for i := range g1s {
+		_, g1s[i], _ = RandomG1(rand.Reader)
+		_, g2s[i], _ = RandomG2(rand.Reader)
+	}
+
+	b.Run("Pair", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ret := Pair(g1s[0], g2s[0])
+			for j := 1; j < n; j++ {
+				ret.Add(ret, Pair(g1s[j], g2s[j]))
+			}
+		}
+	})
+	b.Run("PairingProduct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PairingProduct(g1s, g2s)
+		}
+	})
+}
+
+// BenchmarkPairingProductParallel runs PairingProduct on a large batch with
+// GOMAXPROCS set to each power of two up to the number of CPUs.
+func BenchmarkPairingProductParallel(b *testing.B) {
+	const n = 64
+	g1s, g2s := make([]*G1, n), make([]*G2, n)
+	for i := range g1s {
+		_, g1s[i], _ = RandomG1(rand.Reader)
+		_, g2s[i], _ = RandomG2(rand.Reader)
+	}
+
+	for procs := 1; procs <= runtime.NumCPU(); procs *= 2 {
+		b.Run(fmt.Sprintf("GOMAXPROCS=%d", procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			for i := 0; i < b.N; i++ {
+				PairingProduct(g1s, g2s)
+			}
+		})
+	}
+}
+
+func BenchmarkPairExp(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+	g1, g2 := &G1{curveGen}, &G2{twistGen}
+
+	b.Run("PairThenScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e := Pair(g1, g2)
+			e.ScalarMult(e, k)
+		}
+	})
+	b.Run("PairExp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PairExp(g1, g2, k)
+		}
+	})
+	b.Run("PairExpG1", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PairExpG1(g1, g2, k)
+		}
+	})
+}
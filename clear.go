@@ -0,0 +1,136 @@
+package bn256
+
+import (
+	"math/big"
+	"runtime"
+)
+
+// Go has no way to guarantee that a secret is gone from memory: the garbage
+// collector may have moved or copied a value, and earlier results of an
+// arithmetic operation often live on in memory that was freed but not
+// overwritten. The functions in this file are a best effort. They overwrite
+// the memory a value occupies now, through functions that aren't inlined and
+// with runtime.KeepAlive after the writes, so that the compiler can't drop
+// them as dead stores.
+
+//go:noinline
+func clearWords(w []uint64) {
+	for i := range w {
+		w[i] = 0
+	}
+	runtime.KeepAlive(w)
+}
+
+//go:noinline
+func clearBigWords(w []big.Word) {
+	for i := range w {
+		w[i] = 0
+	}
+	runtime.KeepAlive(w)
+}
+
+//go:noinline
+func clearBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	runtime.KeepAlive(b)
+}
+
+// clearDigits overwrites the recoded scalar of the constant-time scalar
+// multiplications.
+//
+//go:noinline
+func clearDigits(d *[ctDigits]int8) {
+	for i := range d {
+		d[i] = 0
+	}
+	runtime.KeepAlive(d)
+}
+
+// Clear overwrites e with zeros.
+func (e *gfP) Clear() { clearWords(e[:]) }
+
+// Clear overwrites e with zeros.
+func (e *gfP2) Clear() {
+	e.x.Clear()
+	e.y.Clear()
+}
+
+// Clear overwrites e with zeros.
+func (e *gfP6) Clear() {
+	e.x.Clear()
+	e.y.Clear()
+	e.z.Clear()
+}
+
+// Clear overwrites e with zeros.
+func (e *gfP12) Clear() {
+	e.x.Clear()
+	e.y.Clear()
+}
+
+// Clear overwrites every coordinate of c with zeros.
+func (c *curvePoint) Clear() {
+	c.x.Clear()
+	c.y.Clear()
+	c.z.Clear()
+	c.t.Clear()
+}
+
+// Clear overwrites every coordinate of c with zeros.
+func (c *twistPoint) Clear() {
+	c.x.Clear()
+	c.y.Clear()
+	c.z.Clear()
+	c.t.Clear()
+}
+
+// Clear overwrites the coordinates of e with zeros, sets e to the point at
+// infinity and then returns e. Use it on points that are secret once they are
+// no longer needed. See ClearScalar for the limits of clearing memory in Go.
+func (e *G1) Clear() *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Clear()
+	e.p.SetInfinity()
+	return e
+}
+
+// Clear overwrites the coordinates of e with zeros, sets e to the point at
+// infinity and then returns e. See G1.Clear.
+func (e *G2) Clear() *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Clear()
+	e.p.SetInfinity()
+	return e
+}
+
+// Clear overwrites the coordinates of e with zeros, sets e to the identity
+// and then returns e. See G1.Clear.
+func (e *GT) Clear() *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Clear()
+	e.p.SetOne()
+	return e
+}
+
+// ClearScalar overwrites the words of k, including the unused capacity of
+// its internal slice, with zeros and sets k to zero. Use it on private keys
+// and other secret scalars once they are no longer needed.
+//
+// It only clears the memory k uses now. big.Int gives no control over its
+// allocations: an operation that made k grow may have left its earlier
+// words in freed memory, and the temporaries of big.Int arithmetic aren't
+// cleared either. The constant-time functions of this package, such as
+// ScalarMultCT, clear the copies of their scalars that they make themselves.
+func ClearScalar(k *big.Int) {
+	w := k.Bits()
+	clearBigWords(w[:cap(w)])
+	k.SetInt64(0)
+}
@@ -0,0 +1,73 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestClearField(t *testing.T) {
+	_, g, _ := RandomGT(rand.Reader)
+	e := (&gfP12{}).Set(g.p)
+	e.Clear()
+	if *e != (gfP12{}) {
+		t.Fatal("gfP12.Clear left non-zero words")
+	}
+}
+
+func TestClearPoints(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+	_, c, _ := RandomGT(rand.Reader)
+
+	// Clear the internal points first and check that no word survives.
+	p1, p2 := &curvePoint{}, &twistPoint{}
+	p1.Set(a.p)
+	p2.Set(b.p)
+	p1.Clear()
+	p2.Clear()
+	if *p1 != (curvePoint{}) || *p2 != (twistPoint{}) {
+		t.Fatal("Clear left non-zero coordinates")
+	}
+
+	if !a.Clear().p.IsInfinity() {
+		t.Fatal("G1.Clear didn't set the point at infinity")
+	}
+	if !b.Clear().p.IsInfinity() {
+		t.Fatal("G2.Clear didn't set the point at infinity")
+	}
+	if !c.Clear().p.IsOne() {
+		t.Fatal("GT.Clear didn't set the identity")
+	}
+	for _, m := range [][]byte{a.Marshal(), b.Marshal()} {
+		for _, v := range m {
+			if v != 0 {
+				t.Fatalf("cleared point marshals to %x", m)
+			}
+		}
+	}
+
+	// The zero value can be cleared too.
+	new(G1).Clear()
+	new(G2).Clear()
+	new(GT).Clear()
+}
+
+func TestClearScalar(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	k.Mul(k, k)
+	w := k.Bits()
+	w = w[:cap(w)]
+
+	ClearScalar(k)
+	if k.Sign() != 0 {
+		t.Fatalf("ClearScalar left %v", k)
+	}
+	for i, v := range w {
+		if v != 0 {
+			t.Fatalf("word %d of the scalar is %x after ClearScalar", i, v)
+		}
+	}
+
+	ClearScalar(new(big.Int))
+}
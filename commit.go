@@ -0,0 +1,40 @@
+package bn256
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Commit returns the Pedersen vector commitment ∑ msgs[i]·gens[i] + blind·h.
+// The generators and h must be independent, with discrete logarithms with
+// respect to each other that nobody knows, such as the points returned by
+// DeriveGenerators. The commitment is then binding, and it is hiding as long
+// as blind is uniformly random modulo Order and kept secret.
+//
+// Scalars are reduced modulo Order. It returns an error if the number of
+// messages doesn't match the number of generators. Commit uses the bucket
+// method of G1MultiScalarMult and is not constant time.
+func Commit(msgs []*big.Int, blind *big.Int, gens []*G1, h *G1) (*G1, error) {
+	if len(msgs) != len(gens) {
+		return nil, errors.New("bn256: number of messages doesn't match the number of generators")
+	}
+
+	points := make([]*G1, 0, len(gens)+1)
+	points = append(points, gens...)
+	points = append(points, h)
+	scalars := make([]*big.Int, 0, len(msgs)+1)
+	scalars = append(scalars, msgs...)
+	scalars = append(scalars, blind)
+	return G1MultiScalarMult(points, scalars, ReduceScalars)
+}
+
+// VerifyCommit reports whether commit opens to msgs and blind, that is whether
+// it equals Commit(msgs, blind, gens, h). It returns false if the number of
+// messages doesn't match the number of generators.
+func VerifyCommit(commit *G1, msgs []*big.Int, blind *big.Int, gens []*G1, h *G1) bool {
+	want, err := Commit(msgs, blind, gens, h)
+	if err != nil {
+		return false
+	}
+	return commit.Equal(want)
+}
@@ -0,0 +1,67 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestCommit(t *testing.T) {
+	const n = 4
+	gens, err := DeriveGenerators([]byte("commit test"), n+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := gens[n]
+	gens = gens[:n]
+
+	msgs := make([]*big.Int, n)
+	for i := range msgs {
+		msgs[i], _ = rand.Int(rand.Reader, Order)
+	}
+	blind, _ := rand.Int(rand.Reader, Order)
+
+	c, err := Commit(msgs, blind, gens, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyCommit(c, msgs, blind, gens, h) {
+		t.Fatal("valid opening rejected")
+	}
+
+	want := new(G1).ScalarMult(h, blind)
+	for i := range msgs {
+		want.Add(want, new(G1).ScalarMult(gens[i], msgs[i]))
+	}
+	if !c.Equal(want) {
+		t.Fatal("commitment doesn't match ∑ mᵢ·Gᵢ + r·H")
+	}
+
+	// Binding: a changed message or blinding factor doesn't open c.
+	other := append([]*big.Int(nil), msgs...)
+	other[1] = new(big.Int).Add(msgs[1], big.NewInt(1))
+	if VerifyCommit(c, other, blind, gens, h) {
+		t.Fatal("opening with a different message accepted")
+	}
+	if VerifyCommit(c, msgs, new(big.Int).Add(blind, big.NewInt(1)), gens, h) {
+		t.Fatal("opening with a different blinding factor accepted")
+	}
+
+	// Hiding: the same messages with a fresh blinding factor give a different
+	// commitment.
+	blind2, _ := rand.Int(rand.Reader, Order)
+	c2, err := Commit(msgs, blind2, gens, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c2.Equal(c) {
+		t.Fatal("commitments with different blinding factors are equal")
+	}
+
+	if _, err := Commit(msgs[:n-1], blind, gens, h); err == nil {
+		t.Fatal("Commit accepted mismatched lengths")
+	}
+	if VerifyCommit(c, msgs[:n-1], blind, gens, h) {
+		t.Fatal("VerifyCommit accepted mismatched lengths")
+	}
+}
@@ -0,0 +1,140 @@
+// +build bn256compare
+
+package bn256
+
+// The benchmarks in this file run the same workloads against this package and
+// golang.org/x/crypto/bn256, which implements the same curve. They are behind
+// the bn256compare build tag:
+//
+//	go test -tags bn256compare -run XXX -bench Compare
+//
+// github.com/cloudflare/bn256 is this module's own import path, so it can't be
+// benchmarked alongside it; check out that module and run its benchmarks
+// separately instead.
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"math/big"
+
+	"golang.org/x/crypto/bn256"
+)
+
+// compareLib adapts one implementation to the benchmarked workloads. Points are
+// fixed when the adapter is created so that only the operation is timed.
+type compareLib struct {
+	name         string
+	g1ScalarMult func(k *big.Int)
+	g2ScalarMult func(k *big.Int)
+	gtScalarMult func(k *big.Int)
+	pair         func()
+	marshalG1    func() []byte
+	unmarshalG1  func(m []byte)
+	marshalG2    func() []byte
+	unmarshalG2  func(m []byte)
+}
+
+func compareLibs(b *testing.B) []compareLib {
+	k, _ := rand.Int(rand.Reader, Order)
+
+	g1 := new(G1).ScalarBaseMult(k)
+	g2 := new(G2).ScalarBaseMult(k)
+	gt := Pair(g1, g2)
+	this := compareLib{
+		name:         "bn256",
+		g1ScalarMult: func(k *big.Int) { new(G1).ScalarMult(g1, k) },
+		g2ScalarMult: func(k *big.Int) { new(G2).ScalarMult(g2, k) },
+		gtScalarMult: func(k *big.Int) { new(GT).ScalarMult(gt, k) },
+		pair:         func() { Pair(g1, g2) },
+		marshalG1:    g1.Marshal,
+		unmarshalG1: func(m []byte) {
+			if _, err := new(G1).Unmarshal(m); err != nil {
+				b.Fatal(err)
+			}
+		},
+		marshalG2: g2.Marshal,
+		unmarshalG2: func(m []byte) {
+			if _, err := new(G2).Unmarshal(m); err != nil {
+				b.Fatal(err)
+			}
+		},
+	}
+
+	xg1 := new(bn256.G1).ScalarBaseMult(k)
+	xg2 := new(bn256.G2).ScalarBaseMult(k)
+	xgt := bn256.Pair(xg1, xg2)
+	x := compareLib{
+		name:         "x-crypto",
+		g1ScalarMult: func(k *big.Int) { new(bn256.G1).ScalarMult(xg1, k) },
+		g2ScalarMult: func(k *big.Int) { new(bn256.G2).ScalarMult(xg2, k) },
+		gtScalarMult: func(k *big.Int) { new(bn256.GT).ScalarMult(xgt, k) },
+		pair:         func() { bn256.Pair(xg1, xg2) },
+		marshalG1:    xg1.Marshal,
+		unmarshalG1: func(m []byte) {
+			if _, ok := new(bn256.G1).Unmarshal(m); !ok {
+				b.Fatal("unmarshal failed")
+			}
+		},
+		marshalG2: xg2.Marshal,
+		unmarshalG2: func(m []byte) {
+			if _, ok := new(bn256.G2).Unmarshal(m); !ok {
+				b.Fatal("unmarshal failed")
+			}
+		},
+	}
+
+	return []compareLib{this, x}
+}
+
+func BenchmarkCompare(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+
+	for _, lib := range compareLibs(b) {
+		lib := lib
+		b.Run("G1ScalarMult/"+lib.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lib.g1ScalarMult(k)
+			}
+		})
+		b.Run("G2ScalarMult/"+lib.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lib.g2ScalarMult(k)
+			}
+		})
+		b.Run("GTScalarMult/"+lib.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lib.gtScalarMult(k)
+			}
+		})
+		b.Run("Pair/"+lib.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lib.pair()
+			}
+		})
+		b.Run("MarshalG1/"+lib.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lib.marshalG1()
+			}
+		})
+		b.Run("UnmarshalG1/"+lib.name, func(b *testing.B) {
+			m := lib.marshalG1()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				lib.unmarshalG1(m)
+			}
+		})
+		b.Run("MarshalG2/"+lib.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lib.marshalG2()
+			}
+		})
+		b.Run("UnmarshalG2/"+lib.name, func(b *testing.B) {
+			m := lib.marshalG2()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				lib.unmarshalG2(m)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package bn256
+
+// The Compat functions encode points with the layout of
+// golang.org/x/crypto/bn256:
+//
+//	G₁: x‖y                 (64 bytes)
+//	G₂: x.x‖x.y‖y.x‖y.y     (128 bytes)
+//
+// with 32-byte big-endian coordinates, elements of GF(p²) written as x·i+y
+// with the imaginary part first, and the point at infinity encoded as all
+// zeros. Those libraries implement the same curve as this package, so keys and
+// signatures they stored can be read with UnmarshalCompat and written back for
+// them with MarshalCompat. Marshal already produces the encodings of
+// github.com/cloudflare/bn256. The two differ only for G₂, which Marshal
+// prefixes with a flag byte and encodes as the single byte 0x00 at infinity.
+// Both Compat layouts are also the EIP-197 ones.
+
+// MarshalCompat converts e to the layout of golang.org/x/crypto/bn256.
+func (e *G1) MarshalCompat() []byte {
+	return e.Marshal()
+}
+
+// UnmarshalCompat sets e to the point encoded in m by golang.org/x/crypto/bn256
+// or by MarshalCompat and returns the rest of m. It makes the same checks as
+// Unmarshal.
+func (e *G1) UnmarshalCompat(m []byte) ([]byte, error) {
+	return e.Unmarshal(m)
+}
+
+// MarshalCompat converts e to the layout of golang.org/x/crypto/bn256.
+func (e *G2) MarshalCompat() []byte {
+	return e.MarshalEIP197()
+}
+
+// UnmarshalCompat sets e to the point encoded in m by golang.org/x/crypto/bn256
+// or by MarshalCompat and returns the rest of m. It makes the same checks as
+// Unmarshal, which, like those libraries, doesn't check that the point is in
+// G₂; check untrusted points with IsInSubGroup.
+func (e *G2) UnmarshalCompat(m []byte) ([]byte, error) {
+	return e.UnmarshalEIP197(m)
+}
@@ -0,0 +1,92 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"golang.org/x/crypto/bn256"
+)
+
+func TestCompatVectors(t *testing.T) {
+	// golang.org/x/crypto/bn256 encodings of 2·g₁ and 2·g₂.
+	g1Hex := "08fb501e34aa387f9aa6fecb86184dc21ee5b88d120b5b59e185cac6c5e08965" +
+		"06bc7c16a77faa5fb3fd3f18a4923a51972c4a69cd888483692458151468670d"
+	g2Hex := "847dcea5d6eff089c7a866138d04f11ee3d3a926093681e09d83c0ff0d7055a3" +
+		"797e4195d5ea67643fe4b3f10430a2e69db82de62293283908793a1fdb67b095" +
+		"24e58911e0f04c1adc4b89ec50cc0484aa5680c7cf063aa704ad6190c9916b85" +
+		"8c48feb3db33aba73d185f4ccf4f4e37c088a0a37e4daa81b53eb1ce53eaaddd"
+
+	two := big.NewInt(2)
+	m1, _ := hex.DecodeString(g1Hex)
+	a := new(G1)
+	if _, err := a.UnmarshalCompat(m1); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(new(G1).ScalarBaseMult(two)) || !bytes.Equal(a.MarshalCompat(), m1) {
+		t.Fatal("G1 vector mismatch")
+	}
+
+	m2, _ := hex.DecodeString(g2Hex)
+	b := new(G2)
+	if _, err := b.UnmarshalCompat(m2); err != nil {
+		t.Fatal(err)
+	}
+	if !b.Equal(new(G2).ScalarBaseMult(two)) || !bytes.Equal(b.MarshalCompat(), m2) {
+		t.Fatal("G2 vector mismatch")
+	}
+}
+
+func TestCompatRoundTrip(t *testing.T) {
+	for _, k := range []*big.Int{new(big.Int), big.NewInt(1), randomGF(rand.Reader)} {
+		ours1, theirs1 := new(G1).ScalarBaseMult(k), new(bn256.G1).ScalarBaseMult(k)
+		ours2, theirs2 := new(G2).ScalarBaseMult(k), new(bn256.G2).ScalarBaseMult(k)
+
+		// From golang.org/x/crypto/bn256 to this package and back.
+		m1, m2 := theirs1.Marshal(), theirs2.Marshal()
+		a, b := new(G1), new(G2)
+		if rest, err := a.UnmarshalCompat(m1); err != nil || len(rest) != 0 {
+			t.Fatalf("k = %v: G1.UnmarshalCompat: %v", k, err)
+		}
+		if rest, err := b.UnmarshalCompat(m2); err != nil || len(rest) != 0 {
+			t.Fatalf("k = %v: G2.UnmarshalCompat: %v", k, err)
+		}
+		if !a.Equal(ours1) || !b.Equal(ours2) {
+			t.Fatalf("k = %v: decoded points differ", k)
+		}
+
+		// From this package to golang.org/x/crypto/bn256.
+		if _, ok := new(bn256.G1).Unmarshal(ours1.MarshalCompat()); !ok || !bytes.Equal(ours1.MarshalCompat(), m1) {
+			t.Fatalf("k = %v: G1.MarshalCompat isn't accepted", k)
+		}
+		if _, ok := new(bn256.G2).Unmarshal(ours2.MarshalCompat()); !ok || !bytes.Equal(ours2.MarshalCompat(), m2) {
+			t.Fatalf("k = %v: G2.MarshalCompat isn't accepted", k)
+		}
+
+		// And through the native encoding.
+		b2 := new(G2)
+		if _, err := b2.Unmarshal(b.Marshal()); err != nil || !b2.Equal(ours2) {
+			t.Fatalf("k = %v: native round trip failed: %v", k, err)
+		}
+	}
+
+	// Concatenated encodings are read one after the other.
+	_, g2, _ := RandomG2(rand.Reader)
+	inf := new(G2).ScalarBaseMult(new(big.Int))
+	m := append(g2.MarshalCompat(), inf.MarshalCompat()...)
+	b1, b2 := new(G2), new(G2)
+	rest, err := b1.UnmarshalCompat(m)
+	if err == nil {
+		rest, err = b2.UnmarshalCompat(rest)
+	}
+	if err != nil || len(rest) != 0 || !b1.Equal(g2) || !b2.Equal(inf) {
+		t.Fatalf("concatenated encodings don't decode: %v", err)
+	}
+
+	// Short encodings are rejected.
+	if _, err := new(G2).UnmarshalCompat(g2.Marshal()[:eip197G2Size-1]); err == nil {
+		t.Fatal("UnmarshalCompat accepted a short encoding")
+	}
+}
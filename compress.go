@@ -0,0 +1,309 @@
+package bn256
+
+import (
+	"errors"
+)
+
+// MarshalCompressed converts e into a byte slice of ExpectedG2Len(true) bytes:
+// a flag byte with the parity of y, followed by x.
+func (e *G2) MarshalCompressed() []byte {
+	ret := make([]byte, g2CompressedSize)
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+
+	e.p.MakeAffine()
+	if e.p.IsInfinity() {
+		return ret
+	}
+
+	ret[0] = flagEven | byte(e.p.y.parityCT())
+	temp := &gfP{}
+	montDecode(temp, &e.p.x.x)
+	temp.Marshal(ret[1:])
+	montDecode(temp, &e.p.x.y)
+	temp.Marshal(ret[33:])
+
+	if debug {
+		checkRoundTripG2Compressed(e, ret)
+	}
+	return ret
+}
+
+// parseCompressedFlag checks the length and the flag byte of a compressed
+// point of size bytes at the start of m, and reports whether it is the point
+// at infinity, whose coordinates must then be all zeros.
+func parseCompressedFlag(m []byte, size int) (infinity bool, err error) {
+	if len(m) < size {
+		return false, errors.New("bn256: not enough data")
+	}
+	switch m[0] {
+	case flagInfinity:
+		if !isZeroBytes(m[1:size]) {
+			return false, errors.New("bn256: malformed point")
+		}
+		return true, nil
+	case flagEven, flagOdd:
+		return false, nil
+	}
+	return false, errors.New("bn256: malformed point")
+}
+
+// UnmarshalCompressed sets e to the point encoded in m by MarshalCompressed
+// and returns the rest of m. It returns an error if a coordinate of x is not
+// less than p, if x is not the x-coordinate of a point on the twist or if the
+// point is not in G₂. y is the square root of x³+b whose parity matches the
+// flag byte, so every point has a single encoding.
+//
+// UnmarshalCompressed returns as soon as it finds a problem, so its running
+// time depends on m. Use UnmarshalCompressedConstTime for secret points.
+func (e *G2) UnmarshalCompressed(m []byte) ([]byte, error) {
+	infinity, err := parseCompressedFlag(m, g2CompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	if infinity {
+		e.p.SetInfinity()
+		return m[g2CompressedSize:], nil
+	}
+	if !isLikelyCoordinate(m[1:]) || !isLikelyCoordinate(m[33:]) {
+		return nil, errors.New("bn256: coordinate not less than p")
+	}
+
+	c := &twistPoint{}
+	c.x.x.Unmarshal(m[1:])
+	c.x.y.Unmarshal(m[33:])
+	montEncode(&c.x.x, &c.x.x)
+	montEncode(&c.x.y, &c.x.y)
+
+	y2 := (&gfP2{}).Square(&c.x)
+	y2.Mul(y2, &c.x).Add(y2, twistB)
+	if c.y.sqrtCT(y2) != 1 {
+		return nil, errors.New("bn256: malformed point")
+	}
+	if c.y.parityCT() != uint64(m[0]&1) {
+		c.y.Neg(&c.y)
+	}
+
+	c.z.SetOne()
+	c.t.SetOne()
+	if !c.isInSubGroup() {
+		return nil, errors.New("bn256: point not in G2")
+	}
+	e.p.Set(c)
+	return m[g2CompressedSize:], nil
+}
+
+// UnmarshalCompressedConstTime sets e to the point encoded in m by
+// MarshalCompressed and returns the rest of m. It checks that the point is
+// on the twist and in G₂.
+//
+// Other than the flag byte, which determines whether the point is at
+// infinity, the time it takes doesn't depend on m: ranges are checked without
+// branching, y is computed with a constant-time square root and the subgroup
+// check always runs, and the results are only combined at the end. Use it
+// for points that are secret, for example in blind signature schemes.
+func (e *G2) UnmarshalCompressedConstTime(m []byte) ([]byte, error) {
+	return e.unmarshalCompressedConstTime(m, true)
+}
+
+// unmarshalCompressedConstTime implements UnmarshalCompressedConstTime. The
+// subgroup check is skipped if subgroup is false.
+func (e *G2) unmarshalCompressedConstTime(m []byte, subgroup bool) ([]byte, error) {
+	infinity, err := parseCompressedFlag(m, g2CompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	if infinity {
+		e.p.SetInfinity()
+		return m[g2CompressedSize:], nil
+	}
+
+	c := &twistPoint{}
+	c.x.x.Unmarshal(m[1:])
+	c.x.y.Unmarshal(m[33:])
+	ok := lessThanPCT(&c.x.x) & lessThanPCT(&c.x.y)
+	montEncode(&c.x.x, &c.x.x)
+	montEncode(&c.x.y, &c.x.y)
+
+	y2 := (&gfP2{}).Square(&c.x)
+	y2.Mul(y2, &c.x).Add(y2, twistB)
+	ok &= c.y.sqrtCT(y2)
+
+	c.y.CNeg(&c.y, int(c.y.parityCT()^uint64(m[0]&1)))
+
+	c.z.SetOne()
+	c.t.SetOne()
+	if subgroup {
+		ok &= c.isInSubGroupCT()
+	}
+
+	if ok != 1 {
+		return nil, errors.New("bn256: malformed point")
+	}
+	e.p.Set(c)
+	return m[g2CompressedSize:], nil
+}
+
+// MarshalCompressed converts e into a byte slice of ExpectedG1Len(true) bytes:
+// a flag byte with the parity of y, followed by x.
+func (e *G1) MarshalCompressed() []byte {
+	ret := make([]byte, g1CompressedSize)
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+
+	e.p.MakeAffine()
+	if e.p.IsInfinity() {
+		return ret
+	}
+
+	ret[0] = flagEven | byte(parityCT(&e.p.y))
+	temp := &gfP{}
+	montDecode(temp, &e.p.x)
+	temp.Marshal(ret[1:])
+
+	if debug {
+		checkRoundTripG1Compressed(e, ret)
+	}
+	return ret
+}
+
+// UnmarshalCompressed sets e to the point encoded in m by MarshalCompressed
+// and returns the rest of m. It returns an error if x is not less than p or if
+// x³+b is not a square, so that x is not the x-coordinate of a point on the
+// curve. y is the square root of x³+b whose parity matches the flag byte, so
+// every point has a single encoding. Its running time depends on m.
+func (e *G1) UnmarshalCompressed(m []byte) ([]byte, error) {
+	infinity, err := parseCompressedFlag(m, g1CompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	if infinity {
+		e.p.SetInfinity()
+		return m[g1CompressedSize:], nil
+	}
+	if !isLikelyCoordinate(m[1:]) {
+		return nil, errors.New("bn256: coordinate not less than p")
+	}
+
+	c := &curvePoint{}
+	c.x.Unmarshal(m[1:])
+	montEncode(&c.x, &c.x)
+
+	y2 := &gfP{}
+	gfpMul(y2, &c.x, &c.x)
+	gfpMul(y2, y2, &c.x)
+	gfpAdd(y2, y2, curveB)
+
+	if _, ok := c.y.Sqrt(y2); !ok {
+		return nil, errors.New("bn256: malformed point")
+	}
+	if parityCT(&c.y) != uint64(m[0]&1) {
+		gfpNeg(&c.y, &c.y)
+	}
+
+	c.z = *newGFp(1)
+	c.t = *newGFp(1)
+	if !c.IsOnCurve() {
+		return nil, errors.New("bn256: malformed point")
+	}
+	e.p.Set(c)
+	return m[g1CompressedSize:], nil
+}
+
+// gfP6Coordinates returns the six GF(p) coordinates of e in the order in which
+// they are encoded: x.x, x.y, y.x, y.y, z.x, z.y.
+func gfP6Coordinates(e *gfP6) [6]*gfP {
+	return [6]*gfP{&e.x.x, &e.x.y, &e.y.x, &e.y.y, &e.z.x, &e.z.y}
+}
+
+// MarshalCompressed converts e into a byte slice of gtCompressedSize bytes,
+// half the size of Marshal, using the compression of "On Compressible Pairings
+// and Their Computation", Naehrig, Barreto and Schwabe, for the algebraic
+// torus T₂(GF(p⁶)), which contains GT.
+//
+// An element xω + y of T₂ other than ±1 has x ≠ 0 and is encoded as
+// c = (1 + y)/x, from which it is recovered as (c + ω)/(c - ω). The two
+// exceptions are the boundary of the torus: -1 is not in GT and one is
+// encoded as c = 0, which would otherwise decode to -1. The encoding is
+// only defined for elements of GT.
+func (e *GT) MarshalCompressed() []byte {
+	ret := make([]byte, gtCompressedSize)
+	if e.p == nil {
+		e.p = &gfP12{}
+		e.p.SetOne()
+	}
+
+	c := &gfP6{}
+	if !e.p.x.IsZero() {
+		c.SetOne().Add(c, &e.p.y)
+		c.Mul(c, (&gfP6{}).Invert(&e.p.x))
+	}
+
+	temp := &gfP{}
+	for i, v := range gfP6Coordinates(c) {
+		montDecode(temp, v)
+		temp.Marshal(ret[32*i:])
+	}
+
+	if debug {
+		checkRoundTripGTCompressed(e, ret)
+	}
+	return ret
+}
+
+// UnmarshalCompressed sets e to the element encoded in m by MarshalCompressed
+// and returns the rest of m. It returns an error if a coordinate is not less
+// than p or if the decoded element, which is always in T₂, is not in GT.
+func (e *GT) UnmarshalCompressed(m []byte) ([]byte, error) {
+	return e.unmarshalCompressed(m, true)
+}
+
+// unmarshalCompressed implements UnmarshalCompressed. The subgroup check is
+// skipped if subgroup is false.
+func (e *GT) unmarshalCompressed(m []byte, subgroup bool) ([]byte, error) {
+	if len(m) < gtCompressedSize {
+		return nil, errors.New("bn256: not enough data")
+	}
+
+	c := &gfP6{}
+	for i, v := range gfP6Coordinates(c) {
+		if !isLikelyCoordinate(m[32*i:]) {
+			return nil, errors.New("bn256: coordinate not less than p")
+		}
+		v.Unmarshal(m[32*i:])
+		montEncode(v, v)
+	}
+
+	// (c + ω)/(c - ω) = (c + ω)²/(c² - τ) = ((c² + τ) + 2c·ω)/(c² - τ). τ is
+	// not a square in GF(p⁶), so c² - τ is never zero.
+	g := (&gfP12{}).SetOne()
+	if !c.IsZero() {
+		tau := (&gfP6{}).SetOne()
+		tau.MulTau(tau)
+		c2 := (&gfP6{}).Square(c)
+		inv := (&gfP6{}).Sub(c2, tau)
+		inv.Invert(inv)
+		g.y.Add(c2, tau).Mul(&g.y, inv)
+		g.x.Add(c, c).Mul(&g.x, inv)
+	}
+
+	if subgroup && !g.isInSubGroup() {
+		return nil, errors.New("bn256: element not in GT")
+	}
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Set(g)
+	return m[gtCompressedSize:], nil
+}
@@ -0,0 +1,334 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math"
+	"math/big"
+	mathrand "math/rand"
+	"testing"
+	"time"
+)
+
+func TestGfP2SqrtCT(t *testing.T) {
+	xi := &gfP2{*newGFp(1), *newGFp(3)}
+	src := &vectorSource{seed: "gfP2 sqrt"}
+	for i := 0; i < 32; i++ {
+		a := src.nextGFp2()
+		a.Square(a)
+
+		root := &gfP2{}
+		if root.sqrtCT(a) != 1 {
+			t.Fatalf("no square root found for the square %v", a)
+		}
+		if got := (&gfP2{}).Square(root); *got != *a {
+			t.Fatalf("sqrt(%v)² = %v", a, got)
+		}
+
+		// ξ is not a square, so neither is ξa.
+		if a.Mul(a, xi); root.sqrtCT(a) != 0 {
+			t.Fatalf("square root found for the non-square %v", a)
+		}
+	}
+
+	zero, root := &gfP2{}, &gfP2{}
+	if root.sqrtCT(zero) != 1 || !root.IsZero() {
+		t.Fatalf("sqrt(0) = %v", root)
+	}
+}
+
+// g2CompressedDecoders are the decoders of compressed G₂ points, which must
+// accept and reject the same inputs.
+var g2CompressedDecoders = map[string]func(*G2, []byte) ([]byte, error){
+	"UnmarshalCompressed":          (*G2).UnmarshalCompressed,
+	"UnmarshalCompressedConstTime": (*G2).UnmarshalCompressedConstTime,
+}
+
+func TestG2Compressed(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		_, g, err := RandomG2(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := g.MarshalCompressed()
+		if len(m) != ExpectedG2Len(true) || !IsLikelyG2(m) {
+			t.Fatalf("bad compressed encoding %x", m)
+		}
+		if !bytes.Equal(m[1:], g.Marshal()[1:65]) {
+			t.Fatal("compressed encoding doesn't hold x")
+		}
+
+		// The variable-time decoder of the uncompressed encoding must agree.
+		want := new(G2)
+		if _, err := want.Unmarshal(g.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		for name, unmarshal := range g2CompressedDecoders {
+			got := new(G2)
+			rest, err := unmarshal(got, append(m, 0xff))
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !bytes.Equal(rest, []byte{0xff}) {
+				t.Fatalf("%s: rest is %x", name, rest)
+			}
+			if !got.Equal(want) || !bytes.Equal(got.Marshal(), g.Marshal()) {
+				t.Fatalf("%s: decoded point doesn't match", name)
+			}
+
+			flipped := append([]byte{}, m...)
+			flipped[0] ^= 1
+			if _, err := unmarshal(got, flipped); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !got.Equal(new(G2).Neg(want)) {
+				t.Fatalf("%s: flipped parity doesn't give the negation", name)
+			}
+		}
+	}
+
+	inf := new(G2).ScalarBaseMult(new(big.Int))
+	m := inf.MarshalCompressed()
+	if !isZeroBytes(m) {
+		t.Fatalf("point at infinity encoded as %x", m)
+	}
+	for name, unmarshal := range g2CompressedDecoders {
+		got := new(G2).Set(&G2{twistGen})
+		if _, err := unmarshal(got, m); err != nil || !got.p.IsInfinity() {
+			t.Fatalf("%s: point at infinity decoded as %v, %v", name, got, err)
+		}
+	}
+}
+
+func TestG2CompressedInvalid(t *testing.T) {
+	valid := (&G2{twistGen}).MarshalCompressed()
+
+	outside := twistPointOutsideG2(t, &vectorSource{seed: "compressed outside G2"})
+	notInG2 := (&G2{outside}).MarshalCompressed()
+
+	// Find an x for which x³+b/ξ is not a square.
+	notOnTwist := append([]byte{}, valid...)
+	for {
+		notOnTwist[64]++
+		x := &gfP2{}
+		x.x.Unmarshal(notOnTwist[1:])
+		x.y.Unmarshal(notOnTwist[33:])
+		montEncode(&x.x, &x.x)
+		montEncode(&x.y, &x.y)
+		y2 := (&gfP2{}).Square(x)
+		y2.Mul(y2, x).Add(y2, twistB)
+		if (&gfP2{}).sqrtCT(y2) == 0 {
+			break
+		}
+	}
+
+	notReduced := append([]byte{}, valid...)
+	copy(notReduced[33:], pBytes)
+
+	tests := map[string][]byte{
+		"empty":          nil,
+		"short":          valid[:len(valid)-1],
+		"bad flag":       append([]byte{flagUncompressed}, valid[1:]...),
+		"dirty infinity": append([]byte{flagInfinity}, valid[1:]...),
+		"not reduced":    notReduced,
+		"not on twist":   notOnTwist,
+		"not in G2":      notInG2,
+	}
+	for name, m := range tests {
+		for decoder, unmarshal := range g2CompressedDecoders {
+			if _, err := unmarshal(new(G2), m); err == nil {
+				t.Errorf("%s: %s accepted", decoder, name)
+			}
+		}
+	}
+}
+
+// TestG2CompressedConstTimeTiming compares the running time of
+// UnmarshalCompressedConstTime on points whose square root has to be negated
+// with points whose square root doesn't.
+func TestG2CompressedConstTimeTiming(t *testing.T) {
+	skipUnlessTimingTests(t)
+
+	var classes [2][]byte
+	for classes[0] == nil || classes[1] == nil {
+		_, g, _ := RandomG2(rand.Reader)
+		g.p.MakeAffine()
+		y2 := (&gfP2{}).Square(&g.p.y)
+		root := &gfP2{}
+		root.sqrtCT(y2)
+		negated := *root != g.p.y
+
+		c := 0
+		if negated {
+			c = 1
+		}
+		if classes[c] == nil {
+			classes[c] = g.MarshalCompressed()
+		}
+	}
+
+	const samples = 2000
+	var times [2][]float64
+	e := new(G2)
+	for i := 0; i < 2*samples; i++ {
+		c := mathrand.Intn(2)
+		start := time.Now()
+		e.UnmarshalCompressedConstTime(classes[c])
+		times[c] = append(times[c], float64(time.Since(start)))
+	}
+
+	tStat := welchT(times)
+	if math.Abs(tStat) > 10 {
+		t.Errorf("running time depends on the point: t = %.2f", tStat)
+	}
+}
+
+func TestG1Compressed(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		_, g, err := RandomG1(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := g.MarshalCompressed()
+		if len(m) != ExpectedG1Len(true) || !IsLikelyG1(m) {
+			t.Fatalf("bad compressed encoding %x", m)
+		}
+		if !bytes.Equal(m[1:], g.Marshal()[:32]) {
+			t.Fatal("compressed encoding doesn't hold x")
+		}
+
+		got := new(G1)
+		rest, err := got.UnmarshalCompressed(append(m, 0xff))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(rest, []byte{0xff}) {
+			t.Fatalf("rest is %x", rest)
+		}
+		if !got.Equal(g) || !bytes.Equal(got.Marshal(), g.Marshal()) {
+			t.Fatal("decoded point doesn't match")
+		}
+
+		m[0] ^= 1
+		if _, err := got.UnmarshalCompressed(m); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(new(G1).Neg(g)) {
+			t.Fatal("flipped parity doesn't give the negation")
+		}
+	}
+
+	m := new(G1).ScalarBaseMult(new(big.Int)).MarshalCompressed()
+	if !isZeroBytes(m) {
+		t.Fatalf("point at infinity encoded as %x", m)
+	}
+	got := new(G1).Set(&G1{curveGen})
+	if _, err := got.UnmarshalCompressed(m); err != nil || !got.p.IsInfinity() {
+		t.Fatalf("point at infinity decoded as %v, %v", got, err)
+	}
+}
+
+func TestG1CompressedInvalid(t *testing.T) {
+	valid := (&G1{curveGen}).MarshalCompressed()
+
+	// Find an x for which x³+b is not a square.
+	notOnCurve := append([]byte{}, valid...)
+	for {
+		notOnCurve[32]++
+		x := &gfP{}
+		x.Unmarshal(notOnCurve[1:])
+		montEncode(x, x)
+		y2 := &gfP{}
+		gfpMul(y2, x, x)
+		gfpMul(y2, y2, x)
+		gfpAdd(y2, y2, curveB)
+		if isSquareCT(y2) == 0 {
+			break
+		}
+	}
+
+	tests := map[string][]byte{
+		"empty":          nil,
+		"short":          valid[:len(valid)-1],
+		"bad flag":       append([]byte{flagUncompressed}, valid[1:]...),
+		"dirty infinity": append([]byte{flagInfinity}, valid[1:]...),
+		"not reduced":    append([]byte{flagEven}, pBytes...),
+		"not on curve":   notOnCurve,
+	}
+	for name, m := range tests {
+		if _, err := new(G1).UnmarshalCompressed(m); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestGTCompressed(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		_, g, err := RandomGT(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := g.MarshalCompressed()
+		if len(m) != gtCompressedSize || 2*len(m) != len(g.Marshal()) {
+			t.Fatalf("compressed encoding is %d bytes", len(m))
+		}
+
+		got := new(GT)
+		rest, err := got.UnmarshalCompressed(append(m, 0xff))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(rest, []byte{0xff}) {
+			t.Fatalf("rest is %x", rest)
+		}
+		if !got.Equal(g) {
+			t.Fatal("decoded element doesn't match")
+		}
+	}
+
+	m := new(GT).ScalarBaseMult(new(big.Int)).MarshalCompressed()
+	if !isZeroBytes(m) {
+		t.Fatalf("one encoded as %x", m)
+	}
+	got := &GT{(&gfP12{}).Set(gfP12Gen)}
+	if _, err := got.UnmarshalCompressed(m); err != nil || !got.p.IsOne() {
+		t.Fatalf("one decoded as %v, %v", got, err)
+	}
+}
+
+func TestGTCompressedInvalid(t *testing.T) {
+	valid := (&GT{gfP12Gen}).MarshalCompressed()
+
+	// Almost every c decodes to an element of T₂ outside of GT.
+	notInGT := append([]byte{}, valid...)
+	notInGT[len(notInGT)-1] ^= 1
+
+	tests := map[string][]byte{
+		"empty":       nil,
+		"short":       valid[:len(valid)-1],
+		"not reduced": append(append([]byte{}, pBytes...), valid[32:]...),
+		"not in GT":   notInGT,
+	}
+	for name, m := range tests {
+		if _, err := new(GT).UnmarshalCompressed(m); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+
+	// Elements outside of GT still decode to themselves without the
+	// subgroup check, as they lie in T₂.
+	g := new(GT)
+	if _, err := g.unmarshalCompressed(notInGT, false); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(g.MarshalCompressed(), notInGT) {
+		t.Fatal("element of T₂ doesn't round-trip")
+	}
+	n := (&gfP12{}).Conjugate(g.p)
+	if !n.Mul(n, g.p).IsOne() {
+		t.Fatal("decoded element isn't in T₂")
+	}
+}
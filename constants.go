@@ -19,6 +19,24 @@ var p = bigFromBase10("650005496956466037327964387423599057428253581076230035718
 // order-1 = (2**5) * 3 * 5743 * 280941149 * 130979359433191 * 491513138693455212421542731357 * 6518589491078791937
 var Order = bigFromBase10("65000549695646603732796438742359905742570406053903786389881062969044166799969")
 
+// orderPlus1Over2 is (Order+1)/2, the exponent of the square root in GT.
+var orderPlus1Over2 = new(big.Int).Rsh(new(big.Int).Add(Order, big.NewInt(1)), 1)
+
+// orderWords is Order as little-endian 64-bit words.
+var orderWords = [4]uint64{0x1a2ef45b57ac7261, 0x2e8d8e12f82b3924, 0xaa6fecb86184dc21, 0x8fb501e34aa387f9}
+
+// orderMinus2 is Order-2, the exponent of the inversion modulo Order.
+var orderMinus2 = [4]uint64{orderWords[0] - 2, orderWords[1], orderWords[2], orderWords[3]}
+
+// orderNp is -Order⁻¹ mod 2⁶⁴ and orderR2 is 2⁵¹² mod Order, the parameters
+// of Montgomery multiplication modulo Order with R = 2²⁵⁶.
+var orderNp uint64 = 0x56417b72d284e5f
+var orderR2 = [4]uint64{0xb5f030132affbc35, 0x85a1f7da0792e95d, 0x26841e5fa6ee4895, 0x3d8f6c73765aefd5}
+
+// sixuSquared is 6u², which is p mod Order. The ψ endomorphism of the twist
+// acts on G₂ as multiplication by this value.
+var sixuSquared = bigFromBase10("254952053719217181996082057820017271814")
+
 // xiToPMinus1Over6 is ξ^((p-1)/6) where ξ = i+3.
 var xiToPMinus1Over6 = &gfP2{gfP{0x25af52988477cdb7, 0x3d81a455ddced86a, 0x227d012e872c2431, 0x179198d3ea65d05}, gfP{0x7407634dd9cca958, 0x36d5bd6c7afb8f26, 0xf4b1c32cebd880fa, 0x6aa7869306f455f}}
 
@@ -58,6 +76,9 @@ var r3 = &gfP{0x2af2dfb9324a5bb8, 0x388f899054f538a4, 0xdf2ff66396b107a7, 0x24eb
 // pPlus1Over4 is (p+1)/4.
 var pPlus1Over4 = [4]uint64{0x86172b1b1782259a, 0x7b96e234482d6d67, 0x6a9bfb2e18613708, 0x23ed4078d2a8e1fe}
 
+// pMinus3Over4 is (p-3)/4.
+var pMinus3Over4 = [4]uint64{0x86172b1b17822599, 0x7b96e234482d6d67, 0x6a9bfb2e18613708, 0x23ed4078d2a8e1fe}
+
 // pMinus2 is p-2.
 var pMinus2 = [4]uint64{0x185cac6c5e089665, 0xee5b88d120b5b59e, 0xaa6fecb86184dc21, 0x8fb501e34aa387f9}
 
@@ -0,0 +1,199 @@
+package bn256
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+const constantsFile = "constants.txt"
+
+// hexWords encodes little-endian 64-bit words as a big-endian hex number,
+// without Montgomery decoding.
+func hexWords(w [4]uint64) string {
+	buf := make([]byte, 32)
+	(*gfP)(&w).Marshal(buf)
+	return hex.EncodeToString(buf)
+}
+
+// hexBig encodes a non-negative integer as a 32-byte big-endian hex number.
+func hexBig(n *big.Int) string {
+	return hex.EncodeToString(n.FillBytes(make([]byte, 32)))
+}
+
+// constantGoldens returns the canonical encoding of every hard-coded constant,
+// one "name = value" line each. Field elements are Montgomery decoded first,
+// except for the Montgomery parameters themselves.
+func constantGoldens() []string {
+	g1 := &curvePoint{}
+	g1.Set(curveGen)
+	g1.MakeAffine()
+	g2 := &twistPoint{}
+	g2.Set(twistGen)
+	g2.MakeAffine()
+
+	lines := []struct {
+		name, value string
+	}{
+		{"u", hexBig(u)},
+		{"p", hexBig(p)},
+		{"Order", hexBig(Order)},
+		{"orderWords", hexWords(orderWords)},
+		{"orderMinus2", hexWords(orderMinus2)},
+		{"orderNp", fmt.Sprintf("%016x", orderNp)},
+		{"orderR2", hexWords(orderR2)},
+		{"sixuSquared", hexBig(sixuSquared)},
+		{"GLVBeta", hexBig(GLVBeta)},
+		{"GLVLambda", hexBig(GLVLambda)},
+		{"p2", hexWords(p2)},
+		{"np", hexWords(np)},
+		{"rN1", hexWords(*rN1)},
+		{"r2", hexWords(*r2)},
+		{"r3", hexWords(*r3)},
+		{"pPlus1Over4", hexWords(pPlus1Over4)},
+		{"pMinus3Over4", hexWords(pMinus3Over4)},
+		{"pMinus2", hexWords(pMinus2)},
+		{"pMinus1Over2", hexWords(pMinus1Over2)},
+		{"s", hexCoordinates(s)},
+		{"sMinus1Over2", hexCoordinates(sMinus1Over2)},
+		{"glvBeta", hexCoordinates(glvBeta)},
+		{"curveB", hexCoordinates(curveB)},
+		{"twistB", hexGFp2(twistB)},
+		{"xiToPMinus1Over6", hexGFp2(xiToPMinus1Over6)},
+		{"xiToPMinus1Over3", hexGFp2(xiToPMinus1Over3)},
+		{"xiToPMinus1Over2", hexGFp2(xiToPMinus1Over2)},
+		{"xiTo2PMinus2Over3", hexGFp2(xiTo2PMinus2Over3)},
+		{"xiToPSquaredMinus1Over3", hexCoordinates(xiToPSquaredMinus1Over3)},
+		{"xiTo2PSquaredMinus2Over3", hexCoordinates(xiTo2PSquaredMinus2Over3)},
+		{"xiToPSquaredMinus1Over6", hexCoordinates(xiToPSquaredMinus1Over6)},
+		{"curveGen", hexCoordinates(&g1.x, &g1.y)},
+		{"twistGen", hexGFp2(&g2.x) + "," + hexGFp2(&g2.y)},
+		{"gfP12Gen", hexGFp12(gfP12Gen)},
+	}
+
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.name + " = " + l.value
+	}
+	return out
+}
+
+func TestConstantGoldens(t *testing.T) {
+	checkGolden(t, filepath.Join("testdata", constantsFile), constantGoldens())
+}
+
+// gfP2Exp returns a^k by square-and-multiply.
+func gfP2Exp(a *gfP2, k *big.Int) *gfP2 {
+	sum := (&gfP2{}).SetOne()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		sum.Square(sum)
+		if k.Bit(i) != 0 {
+			sum.Mul(sum, a)
+		}
+	}
+	return sum
+}
+
+// TestConstantRelations recomputes the constants from their definitions, so
+// that the goldens can't simply be regenerated from a corrupted value.
+func TestConstantRelations(t *testing.T) {
+	poly := func(c ...int64) *big.Int {
+		ret := new(big.Int)
+		for _, ci := range c {
+			ret.Mul(ret, u).Add(ret, big.NewInt(ci))
+		}
+		return ret
+	}
+	check := func(name string, got, want interface{}) {
+		t.Helper()
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s is %v, want %v", name, got, want)
+		}
+	}
+	words := func(n *big.Int) [4]uint64 {
+		var w [4]uint64
+		(*gfP)(&w).Unmarshal(n.FillBytes(make([]byte, 32)))
+		return w
+	}
+	mod := func(n *big.Int, m *big.Int) *big.Int { return n.Mod(n, m) }
+	R := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	check("u", u, new(big.Int).Exp(big.NewInt(1868033), big.NewInt(3), nil))
+	check("p", p, poly(36, 36, 24, 6, 1))
+	check("Order", Order, poly(36, 36, 18, 6, 1))
+	check("orderWords", orderWords, words(Order))
+	check("orderMinus2", orderMinus2, words(new(big.Int).Sub(Order, big.NewInt(2))))
+	R64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	check("orderNp", orderNp, mod(new(big.Int).Neg(new(big.Int).ModInverse(Order, R64)), R64))
+	check("orderR2", orderR2, words(new(big.Int).Exp(R, big.NewInt(2), Order)))
+	check("sixuSquared", sixuSquared, new(big.Int).Sub(p, Order))
+	check("GLVLambda", GLVLambda, poly(36, 18, 6, 1))
+	check("GLVLambda³", new(big.Int).Exp(GLVLambda, big.NewInt(3), Order), 1)
+	check("GLVBeta³", new(big.Int).Exp(GLVBeta, big.NewInt(3), p), 1)
+	check("p2", p2, words(p))
+	check("np", np, words(mod(new(big.Int).Neg(new(big.Int).ModInverse(p, R)), R)))
+	check("rN1", *rN1, words(new(big.Int).ModInverse(mod(new(big.Int).Set(R), p), p)))
+	check("r2", *r2, words(new(big.Int).Exp(R, big.NewInt(2), p)))
+	check("r3", *r3, words(new(big.Int).Exp(R, big.NewInt(3), p)))
+	check("pPlus1Over4", pPlus1Over4, words(new(big.Int).Rsh(new(big.Int).Add(p, big.NewInt(1)), 2)))
+	check("pMinus3Over4", pMinus3Over4, words(new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(3)), 2)))
+	check("pMinus2", pMinus2, words(new(big.Int).Sub(p, big.NewInt(2))))
+	check("pMinus1Over2", pMinus1Over2, words(new(big.Int).Rsh(p, 1)))
+
+	three := newGFp(3)
+	minusThree := newGFp(-3)
+	sq := &gfP{}
+	gfpMul(sq, s, s)
+	check("s²", *sq, *minusThree)
+	half := &gfP{}
+	half.Invert(newGFp(2))
+	sm := &gfP{}
+	gfpSub(sm, s, newGFp(1))
+	gfpMul(sm, sm, half)
+	check("sMinus1Over2", *sMinus1Over2, *sm)
+	check("curveB", *curveB, *three)
+
+	xi := &gfP2{*newGFp(1), *newGFp(3)}
+	check("twistB", *twistB, *(&gfP2{}).Invert(xi).MulScalar((&gfP2{}).Invert(xi), three))
+
+	pMinus1 := new(big.Int).Sub(p, big.NewInt(1))
+	pSquaredMinus1 := new(big.Int).Mul(p, p)
+	pSquaredMinus1.Sub(pSquaredMinus1, big.NewInt(1))
+	frac := func(n *big.Int, num, den int64) *big.Int {
+		return new(big.Int).Div(new(big.Int).Mul(n, big.NewInt(num)), big.NewInt(den))
+	}
+	check("xiToPMinus1Over6", *xiToPMinus1Over6, *gfP2Exp(xi, frac(pMinus1, 1, 6)))
+	check("xiToPMinus1Over3", *xiToPMinus1Over3, *gfP2Exp(xi, frac(pMinus1, 1, 3)))
+	check("xiToPMinus1Over2", *xiToPMinus1Over2, *gfP2Exp(xi, frac(pMinus1, 1, 2)))
+	check("xiTo2PMinus2Over3", *xiTo2PMinus2Over3, *gfP2Exp(xi, frac(pMinus1, 2, 3)))
+	for _, c := range []struct {
+		name     string
+		got      *gfP
+		num, den int64
+	}{
+		{"xiToPSquaredMinus1Over3", xiToPSquaredMinus1Over3, 1, 3},
+		{"xiTo2PSquaredMinus2Over3", xiTo2PSquaredMinus2Over3, 2, 3},
+		{"xiToPSquaredMinus1Over6", xiToPSquaredMinus1Over6, 1, 6},
+	} {
+		want := gfP2Exp(xi, frac(pSquaredMinus1, c.num, c.den))
+		if want.x != (gfP{}) {
+			t.Errorf("%s is not in GF(p)", c.name)
+		}
+		check(c.name, *c.got, want.y)
+	}
+
+	if !curveGen.IsOnCurve() {
+		t.Error("curveGen is not on the curve")
+	}
+	if !twistGen.IsOnCurve() {
+		t.Error("twistGen is not on the twist")
+	}
+	if !twistGen.isInSubGroup() {
+		t.Error("twistGen is not in G₂")
+	}
+	if got := optimalAte(twistGen, curveGen); *got != *gfP12Gen {
+		t.Error("gfP12Gen is not e(curveGen, twistGen)")
+	}
+}
@@ -51,6 +51,18 @@ func (c *curvePoint) IsOnCurve() bool {
 	return *y2 == *x3
 }
 
+// isOnCurveAffineCT returns 1 if (c.x, c.y) satisfies the curve equation and
+// 0 otherwise, ignoring c.z and without branching on the coordinates.
+func (c *curvePoint) isOnCurveAffineCT() uint64 {
+	y2, x3 := &gfP{}, &gfP{}
+	gfpMul(y2, &c.y, &c.y)
+	gfpMul(x3, &c.x, &c.x)
+	gfpMul(x3, x3, &c.x)
+	gfpAdd(x3, x3, curveB)
+
+	return gfpEqual(y2, x3)
+}
+
 func (c *curvePoint) SetInfinity() {
 	c.x = gfP{0}
 	c.y = *newGFp(1)
@@ -62,6 +74,40 @@ func (c *curvePoint) IsInfinity() bool {
 	return c.z == gfP{0}
 }
 
+// Equal reports whether c and a represent the same point. The Jacobian
+// coordinates are compared by cross-multiplication, so neither point needs to
+// be affine, and without branching on them. The point at infinity has z = 0
+// and is only equal to itself, whatever its x and y.
+func (c *curvePoint) Equal(a *curvePoint) bool {
+	zero := &gfP{}
+	inf1, inf2 := gfpEqual(&c.z, zero), gfpEqual(&a.z, zero)
+
+	// x1/z1² = x2/z2² and y1/z1³ = y2/z2³
+	z12, z22 := &gfP{}, &gfP{}
+	gfpMul(z12, &c.z, &c.z)
+	gfpMul(z22, &a.z, &a.z)
+
+	u1, u2 := &gfP{}, &gfP{}
+	gfpMul(u1, &c.x, z22)
+	gfpMul(u2, &a.x, z12)
+
+	s1, s2 := &gfP{}, &gfP{}
+	gfpMul(z22, z22, &a.z)
+	gfpMul(s1, &c.y, z22)
+	gfpMul(z12, z12, &c.z)
+	gfpMul(s2, &a.y, z12)
+
+	// The points are equal if they are both infinity, or if neither is and
+	// their coordinates match.
+	eq := gfpEqual(u1, u2) & gfpEqual(s1, s2) & (1 ^ inf1 ^ inf2)
+	return (eq | inf1&inf2) == 1
+}
+
+// Add sets c to a+b. It handles all exceptional inputs, by branching on them:
+// a point at infinity on either side, a = b (which doubles) and a = -b (which
+// gives infinity). c may alias a or b. The curve has no points of order two,
+// so Double never needs a special case beyond infinity. Add is not constant
+// time.
 func (c *curvePoint) Add(a, b *curvePoint) {
 	if a.IsInfinity() {
 		c.Set(b)
@@ -183,16 +229,26 @@ func (c *curvePoint) Double(a *curvePoint) {
 }
 
 func (c *curvePoint) Mul(a *curvePoint, scalar *big.Int) {
+	c.mul(a, scalar, nil)
+}
+
+// mul sets c to scalar·a with the double-and-add method. If trace isn't nil,
+// it is called with the accumulator after each bit of scalar, from the most
+// significant one; see ScalarMultTrace.
+func (c *curvePoint) mul(a *curvePoint, scalar *big.Int, trace func(*curvePoint)) {
 	sum, t := &curvePoint{}, &curvePoint{}
 	sum.SetInfinity()
 
-	for i := scalar.BitLen(); i >= 0; i-- {
+	for i := scalar.BitLen() - 1; i >= 0; i-- {
 		t.Double(sum)
 		if scalar.Bit(i) != 0 {
 			sum.Add(t, a)
 		} else {
 			sum.Set(t)
 		}
+		if trace != nil {
+			trace(sum)
+		}
 	}
 
 	c.Set(sum)
@@ -222,6 +278,63 @@ func (c *curvePoint) MakeAffine() {
 	c.t = *newGFp(1)
 }
 
+// makeAffineCT converts c to affine form like MakeAffine, but without
+// branching on the coordinates: it always computes the inverse of z, by an
+// exponentiation that is 0 for z = 0, and selects the canonical form of the
+// point at infinity, (0, 1, 0, 0), with gfpCMov. It returns 1 if c is the
+// point at infinity and 0 otherwise.
+//
+// Points that are already affine, with z = 1, are returned as they are.
+// Whether a point is affine only depends on how it was computed, not on its
+// value, so this skips the inversion without leaking anything.
+func (c *curvePoint) makeAffineCT() uint64 {
+	if c.z == *newGFp(1) {
+		return 0
+	}
+
+	zInv := &gfP{}
+	zInv.Invert(&c.z)
+	infinity := gfpEqual(zInv, &gfP{})
+
+	t, zInv2 := &gfP{}, &gfP{}
+	gfpMul(t, &c.y, zInv)
+	gfpMul(zInv2, zInv, zInv)
+
+	gfpMul(&c.x, &c.x, zInv2)
+	gfpMul(&c.y, t, zInv2)
+
+	one, zero := newGFp(1), &gfP{}
+	gfpCMov(&c.y, &c.y, one, infinity)
+	gfpCMov(&c.z, one, zero, infinity)
+	gfpCMov(&c.t, one, zero, infinity)
+	return infinity
+}
+
+// batchMakeAffine is MakeAffine for every point of cs, with a single field
+// inversion for all of them.
+func batchMakeAffine(cs []*curvePoint) {
+	zs := make([]gfP, len(cs))
+	for i, c := range cs {
+		zs[i] = c.z
+	}
+	gfPBatchInvert(zs, zs)
+
+	one := *newGFp(1)
+	zInv2 := &gfP{}
+	for i, c := range cs {
+		if c.IsInfinity() {
+			c.SetInfinity()
+			continue
+		}
+		gfpMul(zInv2, &zs[i], &zs[i])
+		gfpMul(&c.x, &c.x, zInv2)
+		gfpMul(&c.y, &c.y, zInv2)
+		gfpMul(&c.y, &c.y, &zs[i])
+		c.z = one
+		c.t = one
+	}
+}
+
 func (c *curvePoint) Neg(a *curvePoint) {
 	c.x.Set(&a.x)
 	gfpNeg(&c.y, &a.y)
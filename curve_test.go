@@ -0,0 +1,184 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// scaleCurvePoint returns a copy of a with its Jacobian coordinates scaled by
+// a random λ, which represents the same point.
+func scaleCurvePoint(a *curvePoint, src *vectorSource) *curvePoint {
+	l := src.next()
+	l2, l3 := &gfP{}, &gfP{}
+	gfpMul(l2, l, l)
+	gfpMul(l3, l2, l)
+
+	c := &curvePoint{}
+	gfpMul(&c.x, &a.x, l2)
+	gfpMul(&c.y, &a.y, l3)
+	gfpMul(&c.z, &a.z, l)
+	gfpMul(&c.t, &c.z, &c.z)
+	return c
+}
+
+func scaleTwistPoint(a *twistPoint, src *vectorSource) *twistPoint {
+	l := src.nextGFp2()
+	l2 := (&gfP2{}).Square(l)
+	l3 := (&gfP2{}).Mul(l2, l)
+
+	c := &twistPoint{}
+	c.x.Mul(&a.x, l2)
+	c.y.Mul(&a.y, l3)
+	c.z.Mul(&a.z, l)
+	c.t.Square(&c.z)
+	return c
+}
+
+func TestCurvePointAddExceptional(t *testing.T) {
+	src := &vectorSource{seed: "curve add exceptional"}
+	_, g, _ := RandomG1(rand.Reader)
+	p := scaleCurvePoint(g.p, src)
+	q := scaleCurvePoint(g.p, src)
+	negP := &curvePoint{}
+	negP.Neg(p)
+	inf := &curvePoint{}
+	inf.SetInfinity()
+	double := &curvePoint{}
+	double.Double(g.p)
+
+	tests := []struct {
+		name       string
+		a, b, want *curvePoint
+	}{
+		{"P+P", p, p, double},
+		{"P+Q, Q = P", p, q, double},
+		{"P+(-P)", p, negP, inf},
+		{"(-P)+P", negP, p, inf},
+		{"P+∞", p, inf, g.p},
+		{"∞+P", inf, p, g.p},
+		{"∞+∞", inf, inf, inf},
+		{"∞+zero value", inf, &curvePoint{}, inf},
+	}
+	for _, tt := range tests {
+		got := &curvePoint{}
+		got.Add(tt.a, tt.b)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+
+		// The same sum with the output aliasing each input.
+		for i := 0; i < 2; i++ {
+			a, b := &curvePoint{}, &curvePoint{}
+			a.Set(tt.a)
+			b.Set(tt.b)
+			out := a
+			if i == 1 {
+				out = b
+			}
+			out.Add(a, b)
+			if !out.Equal(tt.want) {
+				t.Errorf("%s with aliased operand %d = %v, want %v", tt.name, i, out, tt.want)
+			}
+		}
+	}
+
+	got := &curvePoint{}
+	got.Double(inf)
+	if !got.IsInfinity() {
+		t.Errorf("2·∞ = %v", got)
+	}
+}
+
+func TestTwistPointAddExceptional(t *testing.T) {
+	src := &vectorSource{seed: "twist add exceptional"}
+	_, g, _ := RandomG2(rand.Reader)
+	p := scaleTwistPoint(g.p, src)
+	q := scaleTwistPoint(g.p, src)
+	negP := &twistPoint{}
+	negP.Neg(p)
+	inf := &twistPoint{}
+	inf.SetInfinity()
+	double := &twistPoint{}
+	double.Double(g.p)
+
+	tests := []struct {
+		name       string
+		a, b, want *twistPoint
+	}{
+		{"P+P", p, p, double},
+		{"P+Q, Q = P", p, q, double},
+		{"P+(-P)", p, negP, inf},
+		{"(-P)+P", negP, p, inf},
+		{"P+∞", p, inf, g.p},
+		{"∞+P", inf, p, g.p},
+		{"∞+∞", inf, inf, inf},
+		{"∞+zero value", inf, &twistPoint{}, inf},
+	}
+	for _, tt := range tests {
+		got := &twistPoint{}
+		got.Add(tt.a, tt.b)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+
+		for i := 0; i < 2; i++ {
+			a, b := &twistPoint{}, &twistPoint{}
+			a.Set(tt.a)
+			b.Set(tt.b)
+			out := a
+			if i == 1 {
+				out = b
+			}
+			out.Add(a, b)
+			if !out.Equal(tt.want) {
+				t.Errorf("%s with aliased operand %d = %v, want %v", tt.name, i, out, tt.want)
+			}
+		}
+	}
+
+	got := &twistPoint{}
+	got.Double(inf)
+	if !got.IsInfinity() {
+		t.Errorf("2·∞ = %v", got)
+	}
+}
+
+func TestG1AddExceptional(t *testing.T) {
+	_, p, _ := RandomG1(rand.Reader)
+	negP := new(G1).Neg(p)
+	if got := new(G1).Add(p, negP); !got.p.IsInfinity() {
+		t.Errorf("P+(-P) = %v", got)
+	}
+	double := new(G1).ScalarMult(p, big.NewInt(2))
+	if got := new(G1).Add(p, p); !got.Equal(double) {
+		t.Errorf("P+P = %v", got)
+	}
+	if got := new(G1).Set(p); !got.Add(got, got).Equal(double) {
+		t.Errorf("P+P in place = %v", got)
+	}
+}
+
+func TestBatchMakeAffine(t *testing.T) {
+	cs := make([]*curvePoint, 8)
+	want := make([]*curvePoint, len(cs))
+	for i := range cs {
+		_, g, _ := RandomG1(rand.Reader)
+		// Leave the points in Jacobian coordinates, with z ≠ 1.
+		cs[i] = &curvePoint{}
+		cs[i].Double(g.p)
+		if i == 3 {
+			cs[i].SetInfinity()
+		}
+		want[i] = &curvePoint{}
+		want[i].Set(cs[i])
+		want[i].MakeAffine()
+	}
+
+	batchMakeAffine(cs)
+	for i := range cs {
+		if *cs[i] != *want[i] {
+			t.Fatalf("point %d: got %v, want %v", i, cs[i], want[i])
+		}
+	}
+}
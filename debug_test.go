@@ -0,0 +1,98 @@
+// +build bn256debug
+
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestCheckNonDegenerate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("degenerate pairing wasn't detected")
+		}
+	}()
+
+	checkNonDegenerate((&gfP12{}).Set(gfP12Gen))
+	checkNonDegenerate((&gfP12{}).SetOne())
+}
+
+func TestMarshalRoundTripChecks(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	_, gt, _ := RandomGT(rand.Reader)
+
+	// The marshal functions check their own output in debug builds.
+	g1.Marshal()
+	g1.MarshalPooled().Release()
+	g1.MarshalEIP197()
+	g1.MarshalCompressed()
+	g2.Marshal()
+	g2.MarshalCompressed()
+	g2.MarshalOrdered(RealFirst)
+	g2.MarshalUntwisted()
+	g2.MarshalEIP197()
+	gt.Marshal()
+	gt.Canonical()
+	gt.MarshalCompressed()
+
+	m1, m2, mt := g1.Marshal(), g2.Marshal(), gt.Marshal()
+	m1[len(m1)-1] ^= 1
+	m2[len(m2)-1] ^= 1
+	mt[len(mt)-1] ^= 1
+	checks := map[string]func(){
+		"G1": func() { checkRoundTripG1(g1, m1) },
+		"G2": func() { checkRoundTripG2(g2, m2) },
+		"GT": func() { checkRoundTripGT(gt, mt) },
+		"compressed G1": func() {
+			checkRoundTripG1Compressed(g1, new(G1).Neg(g1).MarshalCompressed())
+		},
+		"compressed G2": func() {
+			checkRoundTripG2Compressed(g2, new(G2).Neg(g2).MarshalCompressed())
+		},
+		"compressed GT": func() {
+			checkRoundTripGTCompressed(gt, new(GT).Neg(gt).MarshalCompressed())
+		},
+	}
+	for name, check := range checks {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: corrupted encoding wasn't detected", name)
+				}
+			}()
+			check()
+		}()
+	}
+}
+
+func TestScalarMultTrace(t *testing.T) {
+	_, p, _ := RandomG1(rand.Reader)
+	k, _ := rand.Int(rand.Reader, Order)
+
+	for _, k := range []*big.Int{k, new(big.Int).Neg(k), big.NewInt(-1), big.NewInt(-6)} {
+		abs := new(big.Int).Abs(k)
+		steps := ScalarMultTrace(k, p)
+		if len(steps) != abs.BitLen() {
+			t.Fatalf("k = %v: got %d steps for a %d-bit scalar", k, len(steps), abs.BitLen())
+		}
+		for i, step := range steps {
+			prefix := new(big.Int).Rsh(abs, uint(abs.BitLen()-1-i))
+			if k.Sign() < 0 {
+				prefix.Neg(prefix)
+			}
+			if !step.Equal(new(G1).ScalarMult(p, prefix)) {
+				t.Fatalf("k = %v: step %d isn't %v·p", k, i, prefix)
+			}
+		}
+		if !steps[len(steps)-1].Equal(new(G1).ScalarMult(p, k)) {
+			t.Fatalf("k = %v: last step isn't the result of ScalarMult", k)
+		}
+	}
+
+	if len(ScalarMultTrace(new(big.Int), p)) != 0 {
+		t.Fatal("zero scalar has steps")
+	}
+}
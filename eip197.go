@@ -0,0 +1,72 @@
+package bn256
+
+import (
+	"errors"
+)
+
+// The EIP-197 functions encode points with the layout used by the Ethereum
+// precompiled contracts for pairing checks (EIP-196 and EIP-197):
+//
+//	G₁: x‖y                   (64 bytes)
+//	G₂: x.im‖x.re‖y.im‖y.re   (128 bytes)
+//
+// Every coordinate is a 32-byte big-endian number that must be less than p,
+// and the point at infinity is encoded as all zeros. An element of GF(p²) is
+// written with its imaginary part first, which matches the x·i+y layout of
+// gfP2, so the G₂ encoding is the output of G2.Marshal without its flag byte.
+//
+// Note that Ethereum's alt_bn128 (also called BN254) is a different curve from
+// the one implemented by this package: only the layout is shared. Points taken
+// from Ethereum transactions are not, in general, points of this package's
+// groups and will be rejected by the unmarshal functions.
+const (
+	eip197G1Size = 2 * 32
+	eip197G2Size = 4 * 32
+)
+
+// MarshalEIP197 converts e to the EIP-197 layout.
+func (e *G1) MarshalEIP197() []byte {
+	return e.Marshal()
+}
+
+// UnmarshalEIP197 sets e to the point encoded in m with the EIP-197 layout and
+// returns the rest of m.
+func (e *G1) UnmarshalEIP197(m []byte) ([]byte, error) {
+	if len(m) < eip197G1Size {
+		return nil, errors.New("bn256: not enough data")
+	}
+	if !isLikelyCoordinate(m) || !isLikelyCoordinate(m[32:]) {
+		return nil, errors.New("bn256: coordinate not less than p")
+	}
+	return e.Unmarshal(m)
+}
+
+// MarshalEIP197 converts e to the EIP-197 layout.
+func (e *G2) MarshalEIP197() []byte {
+	m := e.Marshal()
+	if len(m) == g2InfinitySize {
+		return make([]byte, eip197G2Size)
+	}
+	return m[1:]
+}
+
+// UnmarshalEIP197 sets e to the point encoded in m with the EIP-197 layout and
+// returns the rest of m.
+func (e *G2) UnmarshalEIP197(m []byte) ([]byte, error) {
+	if len(m) < eip197G2Size {
+		return nil, errors.New("bn256: not enough data")
+	}
+	for i := 0; i < eip197G2Size; i += 32 {
+		if !isLikelyCoordinate(m[i:]) {
+			return nil, errors.New("bn256: coordinate not less than p")
+		}
+	}
+
+	buf := make([]byte, g2Size)
+	buf[0] = flagUncompressed
+	copy(buf[1:], m[:eip197G2Size])
+	if _, err := e.Unmarshal(buf); err != nil {
+		return nil, err
+	}
+	return m[eip197G2Size:], nil
+}
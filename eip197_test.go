@@ -0,0 +1,85 @@
+package bn256
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+func TestEIP197RoundTrip(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+
+	for _, p := range []*G1{a, new(G1).ScalarBaseMult(Order)} {
+		m := p.MarshalEIP197()
+		if len(m) != eip197G1Size {
+			t.Fatalf("G₁ encoding is %d bytes long", len(m))
+		}
+		q := new(G1)
+		if _, err := q.UnmarshalEIP197(m); err != nil {
+			t.Fatal(err)
+		}
+		if !q.Equal(p) {
+			t.Fatal("G₁ point doesn't round-trip")
+		}
+	}
+
+	for _, p := range []*G2{b, new(G2).ScalarBaseMult(Order)} {
+		m := p.MarshalEIP197()
+		if len(m) != eip197G2Size {
+			t.Fatalf("G₂ encoding is %d bytes long", len(m))
+		}
+		q := new(G2)
+		if _, err := q.UnmarshalEIP197(m); err != nil {
+			t.Fatal(err)
+		}
+		if !q.Equal(p) {
+			t.Fatal("G₂ point doesn't round-trip")
+		}
+	}
+
+	if !bytes.Equal(new(G2).ScalarBaseMult(Order).MarshalEIP197(), make([]byte, eip197G2Size)) {
+		t.Fatal("G₂ point at infinity isn't all zeros")
+	}
+}
+
+func TestEIP197Vectors(t *testing.T) {
+	decode := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	// The generator of alt_bn128's G₁ is (1, 2). The two curves share the
+	// equation y² = x³ + 3, so it is a point here too: the negation of this
+	// package's generator (1, -2).
+	ethG1 := decode("0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002")
+	g1 := new(G1)
+	if _, err := g1.UnmarshalEIP197(ethG1); err != nil {
+		t.Fatal(err)
+	}
+	if !g1.Equal(new(G1).Neg(&G1{curveGen})) {
+		t.Fatal("(1, 2) isn't the negated generator")
+	}
+
+	// The generator of alt_bn128's G₂ lies on a different twist, so it must be
+	// rejected.
+	ethG2 := decode("198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c2" +
+		"1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed" +
+		"090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b" +
+		"12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa")
+	if _, err := new(G2).UnmarshalEIP197(ethG2); err == nil {
+		t.Fatal("alt_bn128's G₂ generator was accepted")
+	}
+
+	// Coordinates must be reduced.
+	unreduced := append(append([]byte{}, pBytes...), ethG1[32:]...)
+	if _, err := new(G1).UnmarshalEIP197(unreduced); err == nil {
+		t.Fatal("unreduced x-coordinate was accepted")
+	}
+}
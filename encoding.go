@@ -0,0 +1,175 @@
+package bn256
+
+import (
+	"bytes"
+)
+
+// Encoded sizes of the group elements. Every coordinate is a 256-bit
+// big-endian number.
+//
+// G₁ points are encoded as x‖y, with the point at infinity encoded as all
+// zeros. Compressed G₁ points are encoded as a flag byte followed by x, where
+// the flag is 0x00 for the point at infinity (x is then all zeros) and 0x02 or
+// 0x03 for a point whose y is even or odd, respectively.
+//
+// G₂ points are encoded as the flag byte 0x01 followed by x.x‖x.y‖y.x‖y.y, or
+// as the single byte 0x00 for the point at infinity. Compressed G₂ points use
+// the same flags as compressed G₁ points, followed by x.x‖x.y. The parity of
+// y = y.x·i + y.y is that of y.y, or of y.x if y.y is zero.
+//
+// GT elements are encoded as their twelve coordinates over GF(p). Compressed
+// GT elements are encoded as the six coordinates over GF(p) of their torus
+// representation, an element of GF(p⁶), in the same order.
+const (
+	g1Size           = 2 * 32
+	g1CompressedSize = 1 + 32
+	g2Size           = 1 + 4*32
+	g2InfinitySize   = 1
+	g2CompressedSize = 1 + 2*32
+	gtSize           = 12 * 32
+	gtCompressedSize = 6 * 32
+)
+
+// Flags of compressed and G₂ encodings.
+const (
+	flagInfinity     = 0x00
+	flagUncompressed = 0x01
+	flagEven         = 0x02
+	flagOdd          = 0x03
+)
+
+// pBytes is p as a 32-byte big-endian number.
+var pBytes = p.Bytes()
+
+// isLikelyCoordinate reports whether the 32-byte big-endian number in b is
+// less than p.
+func isLikelyCoordinate(b []byte) bool {
+	return bytes.Compare(b[:32], pBytes) < 0
+}
+
+func isZeroBytes(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ExpectedG1Len returns the length of an encoded G₁ point, in its compressed
+// or uncompressed form.
+func ExpectedG1Len(compressed bool) int {
+	if compressed {
+		return g1CompressedSize
+	}
+	return g1Size
+}
+
+// ExpectedG2Len returns the length of an encoded G₂ point, in its compressed
+// or uncompressed form. Uncompressed points at infinity are encoded as a
+// single byte.
+func ExpectedG2Len(compressed bool) int {
+	if compressed {
+		return g2CompressedSize
+	}
+	return g2Size
+}
+
+// IsLikelyG1 reports whether data has the length, flags and coordinate ranges
+// of an encoded G₁ point, either compressed or not. It does no curve
+// arithmetic, so a true result doesn't mean Unmarshal will succeed.
+func IsLikelyG1(data []byte) bool {
+	switch len(data) {
+	case g1Size:
+		return isLikelyCoordinate(data) && isLikelyCoordinate(data[32:])
+	case g1CompressedSize:
+		switch data[0] {
+		case flagInfinity:
+			return isZeroBytes(data[1:])
+		case flagEven, flagOdd:
+			return isLikelyCoordinate(data[1:])
+		}
+	}
+	return false
+}
+
+// IsLikelyG2 reports whether data has the length, flags and coordinate ranges
+// of an encoded G₂ point, either compressed or not. It does no curve
+// arithmetic, so a true result doesn't mean Unmarshal will succeed.
+func IsLikelyG2(data []byte) bool {
+	switch len(data) {
+	case g2InfinitySize:
+		return data[0] == flagInfinity
+	case g2Size:
+		if data[0] != flagUncompressed {
+			return false
+		}
+		for i := 1; i < g2Size; i += 32 {
+			if !isLikelyCoordinate(data[i:]) {
+				return false
+			}
+		}
+		return true
+	case g2CompressedSize:
+		switch data[0] {
+		case flagInfinity:
+			return isZeroBytes(data[1:])
+		case flagEven, flagOdd:
+			return isLikelyCoordinate(data[1:]) && isLikelyCoordinate(data[33:])
+		}
+	}
+	return false
+}
+
+// G2CoordinateOrder selects the order in which the two GF(p) components of
+// each GF(p²) coordinate of a G₂ point are written.
+type G2CoordinateOrder int
+
+const (
+	// ImaginaryFirst writes c = c.i·i + c.1 as c.i‖c.1. It is the order
+	// used by Marshal and by EIP-197.
+	ImaginaryFirst G2CoordinateOrder = iota
+	// RealFirst writes c = c.i·i + c.1 as c.1‖c.i, with the real component
+	// first. The flag byte and the order of x and y are the same as for
+	// ImaginaryFirst.
+	RealFirst
+)
+
+// swapG2Components swaps the two halves of every GF(p²) coordinate in b, which
+// holds a G₂ encoding without its flag byte.
+func swapG2Components(b []byte) {
+	for i := 0; i+64 <= len(b); i += 64 {
+		for j := 0; j < 32; j++ {
+			b[i+j], b[i+32+j] = b[i+32+j], b[i+j]
+		}
+	}
+}
+
+// MarshalOrdered is like Marshal, but writes the components of each
+// coordinate in the given order.
+func (e *G2) MarshalOrdered(order G2CoordinateOrder) []byte {
+	m := e.Marshal()
+	if order == RealFirst {
+		swapG2Components(m[1:])
+	}
+	if debug {
+		checkRoundTripG2Ordered(e, m, order)
+	}
+	return m
+}
+
+// UnmarshalOrdered is like Unmarshal, but reads the components of each
+// coordinate in the given order.
+func (e *G2) UnmarshalOrdered(m []byte, order G2CoordinateOrder) ([]byte, error) {
+	if order != RealFirst || len(m) < g2Size || m[0] != flagUncompressed {
+		return e.Unmarshal(m)
+	}
+
+	buf := make([]byte, g2Size)
+	copy(buf, m)
+	swapG2Components(buf[1:])
+	if _, err := e.Unmarshal(buf); err != nil {
+		return nil, err
+	}
+	return m[g2Size:], nil
+}
@@ -0,0 +1,136 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestIsLikelyG1(t *testing.T) {
+	_, g, err := RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := g.Marshal()
+	if len(m) != ExpectedG1Len(false) {
+		t.Fatalf("unexpected length %d", len(m))
+	}
+	if !IsLikelyG1(m) {
+		t.Fatal("marshaled point rejected")
+	}
+	if !IsLikelyG1(new(G1).ScalarBaseMult(big.NewInt(0)).Marshal()) {
+		t.Fatal("marshaled point at infinity rejected")
+	}
+
+	compressed := make([]byte, ExpectedG1Len(true))
+	compressed[0] = flagOdd
+	copy(compressed[1:], m[:32])
+	if !IsLikelyG1(compressed) {
+		t.Fatal("compressed encoding rejected")
+	}
+
+	tests := map[string][]byte{
+		"empty":            nil,
+		"short":            m[:len(m)-1],
+		"x not reduced":    append(append([]byte{}, pBytes...), m[32:]...),
+		"y not reduced":    append(append([]byte{}, m[:32]...), pBytes...),
+		"bad flag":         append([]byte{flagUncompressed}, m[:32]...),
+		"dirty infinity":   append([]byte{flagInfinity}, m[:32]...),
+		"compressed not x": append([]byte{flagEven}, pBytes...),
+	}
+	for name, data := range tests {
+		if IsLikelyG1(data) {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestIsLikelyG2(t *testing.T) {
+	_, g, err := RandomG2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := g.Marshal()
+	if len(m) != ExpectedG2Len(false) {
+		t.Fatalf("unexpected length %d", len(m))
+	}
+	if !IsLikelyG2(m) {
+		t.Fatal("marshaled point rejected")
+	}
+	if !IsLikelyG2(new(G2).ScalarBaseMult(big.NewInt(0)).Marshal()) {
+		t.Fatal("marshaled point at infinity rejected")
+	}
+
+	compressed := make([]byte, ExpectedG2Len(true))
+	compressed[0] = flagEven
+	copy(compressed[1:], m[1:65])
+	if !IsLikelyG2(compressed) {
+		t.Fatal("compressed encoding rejected")
+	}
+
+	bad := append([]byte{}, m...)
+	copy(bad[1+3*32:], pBytes)
+	tests := map[string][]byte{
+		"empty":         nil,
+		"short":         m[:len(m)-1],
+		"bad flag":      append([]byte{flagEven}, m[1:]...),
+		"not reduced":   bad,
+		"bad infinity":  {flagUncompressed},
+		"compressed x1": append(append([]byte{flagOdd}, m[1:33]...), pBytes...),
+	}
+	for name, data := range tests {
+		if IsLikelyG2(data) {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestG2CoordinateOrder(t *testing.T) {
+	// The generator of G₂, as (x.i, x.1, y.i, y.1).
+	coords := []string{
+		"2ecca446ff6f3d4d03c76e9b5c752f28bc37b364cb05ac4a37eb32e1c3245970",
+		"8f25386f72c9462b81597d65ae2092c4b97792155dcdaad32b8a6dd41792534c",
+		"2db10ef5233b0fe3962b9ee6a4bbc2b5bde01a54f3513d42df972e128f31bf12",
+		"274e5747e8cafacc3716cc8699db79b22f0e4ff3c23e898f694420a3be3087a5",
+	}
+	// Both encodings of the generator, built from its coordinates above.
+	vectors := []struct {
+		order G2CoordinateOrder
+		hex   string
+	}{
+		{ImaginaryFirst, "01" + coords[0] + coords[1] + coords[2] + coords[3]},
+		{RealFirst, "01" + coords[1] + coords[0] + coords[3] + coords[2]},
+	}
+
+	for _, v := range vectors {
+		want, _ := hex.DecodeString(v.hex)
+		got := (&G2{twistGen}).MarshalOrdered(v.order)
+		if !bytes.Equal(got, want) {
+			t.Errorf("order %d: got %x, want %x", v.order, got, want)
+		}
+
+		g := new(G2)
+		rest, err := g.UnmarshalOrdered(append(want, 0xff), v.order)
+		if err != nil {
+			t.Fatalf("order %d: %v", v.order, err)
+		}
+		if !g.Equal(&G2{twistGen}) || !bytes.Equal(rest, []byte{0xff}) {
+			t.Errorf("order %d: generator doesn't round-trip", v.order)
+		}
+	}
+
+	if !bytes.Equal((&G2{twistGen}).Marshal(), (&G2{twistGen}).MarshalOrdered(ImaginaryFirst)) {
+		t.Error("Marshal doesn't use ImaginaryFirst")
+	}
+
+	inf := new(G2).ScalarBaseMult(Order)
+	m := inf.MarshalOrdered(RealFirst)
+	if !bytes.Equal(m, []byte{flagInfinity}) {
+		t.Errorf("point at infinity encoded as %x", m)
+	}
+	if _, err := new(G2).UnmarshalOrdered(m, RealFirst); err != nil {
+		t.Error(err)
+	}
+}
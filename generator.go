@@ -0,0 +1,93 @@
+package bn256
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// generatorsDST prefixes the domain separation tag used by DeriveGenerators.
+const generatorsDST = "BN256-DERIVE-GENERATORS-"
+
+// gtGeneratorDST is the domain separation tag used by DeriveGTGenerator.
+const gtGeneratorDST = "BN256-DERIVE-GT-GENERATOR"
+
+// Gen1 returns a copy of the generator g₁ of G₁.
+func Gen1() *G1 {
+	e := &G1{&curvePoint{}}
+	e.p.Set(curveGen)
+	return e
+}
+
+// Gen2 returns a copy of the generator g₂ of G₂.
+func Gen2() *G2 {
+	e := &G2{&twistPoint{}}
+	e.p.Set(twistGen)
+	return e
+}
+
+// GenGT returns a copy of the generator e(g₁, g₂) of GT, which
+// GT.ScalarBaseMult uses. It is hard-coded, so GenGT doesn't compute a
+// pairing.
+func GenGT() *GT {
+	return &GT{(&gfP12{}).Set(gfP12Gen)}
+}
+
+// CurveParams holds the parameters of the curve y² = x³ + B over GF(P), for
+// protocols that need them as numbers. Every field is a copy owned by the
+// caller.
+type CurveParams struct {
+	// U is the BN parameter that P and Order are polynomials in.
+	U *big.Int
+	// P is the prime size of the base field.
+	P *big.Int
+	// Order is the prime order r of G₁, G₂ and GT. It is the same value as
+	// the package variable Order.
+	Order *big.Int
+	// B is the constant of the curve equation of G₁, 3.
+	B *big.Int
+	// G1Gen and G2Gen are the generators returned by Gen1 and Gen2.
+	G1Gen *G1
+	G2Gen *G2
+}
+
+// Params returns the parameters of the curve. The values are copied on every
+// call, so changing them doesn't affect the package, and vice versa.
+func Params() *CurveParams {
+	return &CurveParams{
+		U:     new(big.Int).Set(u),
+		P:     new(big.Int).Set(p),
+		Order: new(big.Int).Set(Order),
+		B:     big.NewInt(3),
+		G1Gen: Gen1(),
+		G2Gen: Gen2(),
+	}
+}
+
+// DeriveGTGenerator returns e(HashG1(domain), g₂), a generator of GT derived
+// from domain, for protocols that need generators of GT independent from
+// GenGT. Nobody knows its discrete logarithm with respect to GenGT, and the
+// same domain always yields the same element.
+func DeriveGTGenerator(domain []byte) *GT {
+	return HashToGT(domain, []byte(gtGeneratorDST))
+}
+
+// DeriveGenerators returns n points of G₁ derived from domain by hashing the
+// index of each point into the curve. Nobody knows the discrete logarithms
+// of the points with respect to each other or to the generator, which makes
+// them suitable as independent bases, e.g. for Pedersen commitments. The same
+// domain always yields the same points.
+func DeriveGenerators(domain []byte, n int) ([]*G1, error) {
+	if n < 0 {
+		return nil, errors.New("bn256: negative number of generators")
+	}
+
+	dst := append([]byte(generatorsDST), domain...)
+	ret := make([]*G1, n)
+	var index [4]byte
+	for i := range ret {
+		binary.BigEndian.PutUint32(index[:], uint32(i))
+		ret[i] = HashG1(index[:], dst)
+	}
+	return ret, nil
+}
@@ -0,0 +1,132 @@
+package bn256
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestDeriveGenerators(t *testing.T) {
+	gens, err := DeriveGenerators([]byte("test"), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := DeriveGenerators([]byte("test"), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := DeriveGenerators([]byte("other"), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	for i, g := range gens {
+		m := g.Marshal()
+		if !g.p.IsOnCurve() {
+			t.Fatalf("generator %d isn't on the curve", i)
+		}
+		if seen[string(m)] {
+			t.Fatalf("generator %d is repeated", i)
+		}
+		seen[string(m)] = true
+
+		if i < len(again) && !bytes.Equal(m, again[i].Marshal()) {
+			t.Fatalf("generator %d isn't deterministic", i)
+		}
+		if bytes.Equal(m, other[i].Marshal()) {
+			t.Fatalf("generator %d doesn't depend on the domain", i)
+		}
+	}
+
+	if _, err := DeriveGenerators(nil, -1); err == nil {
+		t.Fatal("negative count accepted")
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	g1, g2, gt := Gen1(), Gen2(), GenGT()
+	if !bytes.Equal(g1.Marshal(), new(G1).ScalarBaseMult(big.NewInt(1)).Marshal()) {
+		t.Fatal("Gen1 isn't the generator")
+	}
+	if !bytes.Equal(g2.Marshal(), new(G2).ScalarBaseMult(big.NewInt(1)).Marshal()) {
+		t.Fatal("Gen2 isn't the generator")
+	}
+	if !gt.Equal(Pair(g1, g2)) {
+		t.Fatal("GenGT != e(Gen1, Gen2)")
+	}
+
+	// The accessors return copies.
+	g1.Add(g1, g1)
+	g2.Add(g2, g2)
+	gt.Add(gt, gt)
+	if !Gen1().p.Equal(curveGen) || !Gen2().p.Equal(twistGen) || *GenGT().p != *gfP12Gen {
+		t.Fatal("changing a returned generator changed the package generator")
+	}
+}
+
+func TestDeriveGTGenerator(t *testing.T) {
+	g := DeriveGTGenerator([]byte("test"))
+	if !g.Equal(DeriveGTGenerator([]byte("test"))) {
+		t.Fatal("DeriveGTGenerator isn't deterministic")
+	}
+	if g.Equal(DeriveGTGenerator([]byte("other"))) {
+		t.Fatal("DeriveGTGenerator doesn't depend on the domain")
+	}
+	if g.Equal(GenGT()) || g.p.IsOne() {
+		t.Fatal("derived generator is trivial")
+	}
+	if !new(GT).ScalarMult(g, Order).p.IsOne() {
+		t.Fatal("derived generator isn't in GT")
+	}
+}
+
+func TestParams(t *testing.T) {
+	params := Params()
+	if !params.P.ProbablyPrime(20) || !params.Order.ProbablyPrime(20) {
+		t.Fatal("P or Order isn't prime")
+	}
+
+	// P and Order are 36u⁴+36u³+24u²+6u+1 and 36u⁴+36u³+18u²+6u+1.
+	poly := func(c2 int64) *big.Int {
+		ret := big.NewInt(0)
+		for _, c := range []int64{36, 36, c2, 6, 1} {
+			ret.Mul(ret, params.U).Add(ret, big.NewInt(c))
+		}
+		return ret
+	}
+	if params.P.Cmp(poly(24)) != 0 || params.Order.Cmp(poly(18)) != 0 {
+		t.Fatal("P or Order doesn't match U")
+	}
+	if params.Order.Cmp(Order) != 0 || params.B.Cmp(toBigInt(curveB)) != 0 {
+		t.Fatal("Order or B doesn't match the package's values")
+	}
+
+	wantG1 := "0000000000000000000000000000000000000000000000000000000000000001" +
+		"8fb501e34aa387f9aa6fecb86184dc21ee5b88d120b5b59e185cac6c5e089665"
+	wantG2 := "01" +
+		"2ecca446ff6f3d4d03c76e9b5c752f28bc37b364cb05ac4a37eb32e1c3245970" +
+		"8f25386f72c9462b81597d65ae2092c4b97792155dcdaad32b8a6dd41792534c" +
+		"2db10ef5233b0fe3962b9ee6a4bbc2b5bde01a54f3513d42df972e128f31bf12" +
+		"274e5747e8cafacc3716cc8699db79b22f0e4ff3c23e898f694420a3be3087a5"
+	if got := hex.EncodeToString(params.G1Gen.Marshal()); got != wantG1 {
+		t.Fatalf("G1Gen is %s", got)
+	}
+	if got := hex.EncodeToString(params.G2Gen.Marshal()); got != wantG2 {
+		t.Fatalf("G2Gen is %s", got)
+	}
+
+	// The caller owns the values.
+	params.P.SetInt64(1)
+	params.Order.SetInt64(1)
+	params.G1Gen.Double(params.G1Gen)
+	params.G2Gen.Double(params.G2Gen)
+	again := Params()
+	if again.P.Cmp(p) != 0 || Order.Cmp(again.Order) != 0 {
+		t.Fatal("changing the parameters changed the package's values")
+	}
+	if hex.EncodeToString(Gen1().Marshal()) != wantG1 || hex.EncodeToString(again.G2Gen.Marshal()) != wantG2 {
+		t.Fatal("changing the generators changed the package's generators")
+	}
+}
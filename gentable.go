@@ -0,0 +1,129 @@
+package bn256
+
+import (
+	"math/big"
+	"sync"
+)
+
+// The G₁ and G₂ generator tables hold the odd multiples d·16^j·g, for
+// d = 1, 3, …, 15, in genTable[j][(d-1)/2] for each of the ctDigits signed
+// digits that recodeScalarCT produces. A scalar multiplication of the
+// generator is then the sum of one entry per digit, possibly negated, with no
+// doublings at all. Every digit is odd and non-zero, so every row costs one
+// constant-time lookup and one addition, whatever the scalar.
+//
+// Adding the rows from the least significant one upwards, the exceptional
+// cases of MulCT can only happen in the last rows as well: before that, the
+// partial sum is an odd multiple of g smaller than 16^j, and the entry an even
+// multiple larger than it, so neither they nor their difference are
+// multiples of Order.
+//
+// The G₁ table takes 65·8·128 bytes, or 65 KiB, and the G₂ table twice as
+// much. They are only built when they are first needed.
+var (
+	g1GenTableOnce sync.Once
+	g1GenTable     *[ctDigits][8]curvePoint
+
+	g2GenTableOnce sync.Once
+	g2GenTable     *[ctDigits][8]twistPoint
+)
+
+// PrecomputeG1Generator builds the table of multiples of the G₁ generator used
+// by G1.ScalarBaseMult. Calling it is optional: the table is otherwise built
+// on the first call to ScalarBaseMult. The table takes 65 KiB of memory.
+//
+// When built with the bn256small tag the table is never built and
+// PrecomputeG1Generator does nothing.
+func PrecomputeG1Generator() {
+	if smallMemory {
+		return
+	}
+	g1GenTableOnce.Do(func() {
+		table := new([ctDigits][8]curvePoint)
+		base, double := &curvePoint{}, &curvePoint{}
+		base.Set(curveGen)
+		for j := range table {
+			table[j][0].Set(base)
+			double.Double(base)
+			for d := 1; d < len(table[j]); d++ {
+				table[j][d].Add(&table[j][d-1], double)
+				table[j][d].MakeAffine()
+			}
+			for i := 0; i < ctWindow; i++ {
+				base.Double(base)
+			}
+			base.MakeAffine()
+		}
+		g1GenTable = table
+	})
+}
+
+// PrecomputeG2Generator builds the table of multiples of the G₂ generator used
+// by G2.ScalarBaseMult. Calling it is optional: the table is otherwise built
+// on the first call to ScalarBaseMult. The table takes 130 KiB of memory.
+//
+// When built with the bn256small tag the table is never built and
+// PrecomputeG2Generator does nothing.
+func PrecomputeG2Generator() {
+	if smallMemory {
+		return
+	}
+	g2GenTableOnce.Do(func() {
+		table := new([ctDigits][8]twistPoint)
+		base, double := &twistPoint{}, &twistPoint{}
+		base.Set(twistGen)
+		for j := range table {
+			table[j][0].Set(base)
+			double.Double(base)
+			for d := 1; d < len(table[j]); d++ {
+				table[j][d].Add(&table[j][d-1], double)
+				table[j][d].MakeAffine()
+			}
+			for i := 0; i < ctWindow; i++ {
+				base.Double(base)
+			}
+			base.MakeAffine()
+		}
+		g2GenTable = table
+	})
+}
+
+// g1BaseMult sets c to k·curveGen using the generator table, with a fixed
+// sequence of group operations. The scalar is reduced modulo Order.
+func g1BaseMult(c *curvePoint, k *big.Int) {
+	if smallMemory {
+		c.MulCT(curveGen, k)
+		return
+	}
+	PrecomputeG1Generator()
+
+	digits := recodeScalarCT(k)
+	defer clearDigits(&digits)
+	sum, t := &curvePoint{}, &curvePoint{}
+	sum.ctLookup(&g1GenTable[0], digits[0])
+	for j := 1; j < ctDigits; j++ {
+		t.ctLookup(&g1GenTable[j], digits[j])
+		sum.Add(sum, t)
+	}
+	c.Set(sum)
+}
+
+// g2BaseMult sets c to k·twistGen using the generator table, with a fixed
+// sequence of group operations. The scalar is reduced modulo Order.
+func g2BaseMult(c *twistPoint, k *big.Int) {
+	if smallMemory {
+		c.MulCT(twistGen, k)
+		return
+	}
+	PrecomputeG2Generator()
+
+	digits := recodeScalarCT(k)
+	defer clearDigits(&digits)
+	sum, t := &twistPoint{}, &twistPoint{}
+	sum.ctLookup(&g2GenTable[0], digits[0])
+	for j := 1; j < ctDigits; j++ {
+		t.ctLookup(&g2GenTable[j], digits[j])
+		sum.Add(sum, t)
+	}
+	c.Set(sum)
+}
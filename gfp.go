@@ -2,9 +2,9 @@ package bn256
 
 import (
 	"crypto/sha256"
-	"encoding/binary"
 	"fmt"
 	"math/big"
+	"math/bits"
 
 	"golang.org/x/crypto/hkdf"
 )
@@ -27,25 +27,33 @@ func newGFp(x int64) (out *gfP) {
 //
 // L = ceil((256+128)/8)=48, ctr = 0, i = 1
 func hashToBase(msg, dst []byte) *gfP {
+	return hashToBaseComponent(msg, dst, 1)
+}
+
+// hashToBaseComponent is hashToBase for the i-th component of an element of
+// an extension field, starting from one. hashToBase is its first component.
+func hashToBaseComponent(msg, dst []byte, i byte) *gfP {
 	var t [48]byte
-	info := []byte{'H', '2', 'C', byte(0), byte(1)}
+	info := []byte{'H', '2', 'C', byte(0), i}
 	r := hkdf.New(sha256.New, msg, dst, info)
 	if _, err := r.Read(t[:]); err != nil {
 		panic(err)
 	}
-	var x big.Int
-	v := x.SetBytes(t[:]).Mod(&x, p).Bytes()
-	v32 := [32]byte{}
-	for i := len(v) - 1; i >= 0; i-- {
-		v32[len(v)-1-i] = v[i]
-	}
-	u := &gfP{
-		binary.LittleEndian.Uint64(v32[0*8 : 1*8]),
-		binary.LittleEndian.Uint64(v32[1*8 : 2*8]),
-		binary.LittleEndian.Uint64(v32[2*8 : 3*8]),
-		binary.LittleEndian.Uint64(v32[3*8 : 4*8]),
-	}
-	montEncode(u, u)
+
+	// Reduce the 384-bit number hi·2²⁵⁶ + lo in constant time. Montgomery
+	// encoding reduces any 256-bit number, and multiplying by r2 (which is
+	// 2²⁵⁶ in Montgomery form) shifts hi into place.
+	hi, lo := &gfP{}, &gfP{}
+	var buf [32]byte
+	copy(buf[16:], t[:16])
+	hi.Unmarshal(buf[:])
+	lo.Unmarshal(t[16:])
+	montEncode(hi, hi)
+	montEncode(lo, lo)
+	gfpMul(hi, hi, r2)
+
+	u := &gfP{}
+	gfpAdd(u, hi, lo)
 	return u
 }
 
@@ -82,9 +90,51 @@ func (e *gfP) Invert(f *gfP) {
 	e.exp(f, pMinus2)
 }
 
-func (e *gfP) Sqrt(f *gfP) {
-	// Since p = 4k+3, then e = f^(k+1) is a root of f.
+// gfPBatchInvert sets out[i] to the inverse of in[i] with a single inversion,
+// using Montgomery's trick. Zero elements are left as zero. out and in may be
+// the same slice.
+func gfPBatchInvert(out, in []gfP) {
+	// prefix[i] is the product of the non-zero elements of in[:i].
+	prefix := make([]gfP, len(in)+1)
+	prefix[0] = *newGFp(1)
+	for i := range in {
+		if in[i] == (gfP{}) {
+			prefix[i+1] = prefix[i]
+		} else {
+			gfpMul(&prefix[i+1], &prefix[i], &in[i])
+		}
+	}
+
+	inv := &gfP{}
+	inv.Invert(&prefix[len(in)])
+	t := &gfP{}
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i] == (gfP{}) {
+			out[i] = gfP{}
+			continue
+		}
+		gfpMul(t, inv, &prefix[i])
+		gfpMul(inv, inv, &in[i])
+		out[i] = *t
+	}
+}
+
+// Sqrt sets e to a square root of f and returns e and whether f is a square.
+// Since p = 4k+3, the root is f^(k+1), which is checked by squaring it. If f
+// is not a square, e is set to a square root of -f instead, as then
+// (f^(k+1))² = f·f^(2k+1) = -f.
+func (e *gfP) Sqrt(f *gfP) (*gfP, bool) {
+	a := *f
 	e.exp(f, pPlus1Over4)
+	check := &gfP{}
+	gfpMul(check, e, e)
+	return e, *check == a
+}
+
+// IsSquare reports whether e is a square, including zero, by computing its
+// Legendre symbol.
+func (e *gfP) IsSquare() bool {
+	return legendre(e) >= 0
 }
 
 func (e *gfP) Marshal(out []byte) {
@@ -104,9 +154,90 @@ func (e *gfP) Unmarshal(in []byte) {
 	}
 }
 
+// bigInt returns e, converted out of Montgomery form, as a big.Int in [0, p).
+func (e *gfP) bigInt() *big.Int {
+	t := &gfP{}
+	montDecode(t, e)
+	buf := make([]byte, 32)
+	t.Marshal(buf)
+	return new(big.Int).SetBytes(buf)
+}
+
 func montEncode(c, a *gfP) { gfpMul(c, a, r2) }
 func montDecode(c, a *gfP) { gfpMul(c, a, &gfP{1}) }
 
+// gfpCMov sets c to a if cond is 0 and to b if cond is 1, without branching on
+// cond.
+func gfpCMov(c, a, b *gfP, cond uint64) {
+	mask := -cond
+	for i := range c {
+		c[i] = a[i] ^ (mask & (a[i] ^ b[i]))
+	}
+}
+
+// CMove sets e to a if cond is 0 and to b if cond is 1. cond must be 0 or 1.
+// It blends the words of a and b with a mask derived from cond, so it has no
+// branches or memory accesses that depend on cond.
+func (e *gfP) CMove(a, b *gfP, cond int) {
+	gfpCMov(e, a, b, uint64(cond))
+}
+
+// CNeg sets e to a if cond is 0 and to -a if cond is 1. cond must be 0 or 1.
+// Like CMove, it doesn't branch on cond: -a is always computed.
+func (e *gfP) CNeg(a *gfP, cond int) {
+	neg := &gfP{}
+	gfpNeg(neg, a)
+	gfpCMov(e, a, neg, uint64(cond))
+}
+
+// gfpEqual returns 1 if a and b are equal and 0 otherwise, without branching
+// on their values.
+func gfpEqual(a, b *gfP) uint64 {
+	var d uint64
+	for i := range a {
+		d |= a[i] ^ b[i]
+	}
+	// d|-d has its top bit set iff d != 0.
+	return 1 ^ ((d | -d) >> 63)
+}
+
+// sign0CT returns 1 if e ≥ (p-1)/2 and 0 otherwise, in constant time. It is
+// the constant-time counterpart of sign0.
+func sign0CT(e *gfP) uint64 {
+	x := &gfP{}
+	montDecode(x, e)
+	var borrow uint64
+	for i := range x {
+		_, borrow = bits.Sub64(x[i], pMinus1Over2[i], borrow)
+	}
+	return 1 ^ borrow
+}
+
+// lessThanPCT returns 1 if e, which must not be in Montgomery form, is less
+// than p and 0 otherwise, in constant time.
+func lessThanPCT(e *gfP) uint64 {
+	var borrow uint64
+	for i := range e {
+		_, borrow = bits.Sub64(e[i], p2[i], borrow)
+	}
+	return borrow
+}
+
+// parityCT returns the least significant bit of the canonical value of e.
+func parityCT(e *gfP) uint64 {
+	x := &gfP{}
+	montDecode(x, e)
+	return x[0] & 1
+}
+
+// isSquareCT returns 1 if e is a non-zero square and 0 otherwise, in constant
+// time.
+func isSquareCT(e *gfP) uint64 {
+	f := &gfP{}
+	f.exp(e, pMinus1Over2)
+	return gfpEqual(f, newGFp(1))
+}
+
 func sign0(e *gfP) int {
 	x := &gfP{}
 	montDecode(x, e)
@@ -6,6 +6,7 @@ package bn256
 
 import (
 	"math/big"
+	"math/bits"
 )
 
 // gfP12 implements the field of size p¹² as a quadratic extension of gfP6
@@ -75,6 +76,13 @@ func (e *gfP12) IsOne() bool {
 	return e.x.IsZero() && e.y.IsOne()
 }
 
+// gfp12Equal returns 1 if a and b are equal and 0 otherwise, without
+// branching on their values.
+func gfp12Equal(a, b *gfP12) uint64 {
+	return gfp2Equal(&a.x.x, &b.x.x) & gfp2Equal(&a.x.y, &b.x.y) & gfp2Equal(&a.x.z, &b.x.z) &
+		gfp2Equal(&a.y.x, &b.y.x) & gfp2Equal(&a.y.y, &b.y.y) & gfp2Equal(&a.y.z, &b.y.z)
+}
+
 func (e *gfP12) Conjugate(a *gfP12) *gfP12 {
 	e.x.Neg(&a.x)
 	e.y.Set(&a.y)
@@ -87,6 +95,32 @@ func (e *gfP12) Neg(a *gfP12) *gfP12 {
 	return e
 }
 
+// gfp12CMov sets c to a if cond is 0 and to b if cond is 1, without branching
+// on cond.
+func gfp12CMov(c, a, b *gfP12, cond uint64) {
+	gfp2CMov(&c.x.x, &a.x.x, &b.x.x, cond)
+	gfp2CMov(&c.x.y, &a.x.y, &b.x.y, cond)
+	gfp2CMov(&c.x.z, &a.x.z, &b.x.z, cond)
+	gfp2CMov(&c.y.x, &a.y.x, &b.y.x, cond)
+	gfp2CMov(&c.y.y, &a.y.y, &b.y.y, cond)
+	gfp2CMov(&c.y.z, &a.y.z, &b.y.z, cond)
+}
+
+// CMove sets e to a if cond is 0 and to b if cond is 1, and then returns e.
+// cond must be 0 or 1. It doesn't branch on cond; see gfP.CMove.
+func (e *gfP12) CMove(a, b *gfP12, cond int) *gfP12 {
+	gfp12CMov(e, a, b, uint64(cond))
+	return e
+}
+
+// CNeg sets e to a if cond is 0 and to -a if cond is 1, and then returns e.
+// cond must be 0 or 1. It doesn't branch on cond.
+func (e *gfP12) CNeg(a *gfP12, cond int) *gfP12 {
+	neg := (&gfP12{}).Neg(a)
+	gfp12CMov(e, a, neg, uint64(cond))
+	return e
+}
+
 // Frobenius computes (xω+y)^p = x^p ω·ξ^((p-1)/6) + y^p
 func (e *gfP12) Frobenius(a *gfP12) *gfP12 {
 	e.x.Frobenius(&a.x)
@@ -158,6 +192,136 @@ func (c *gfP12) Exp(a *gfP12, power *big.Int) *gfP12 {
 	return c
 }
 
+// expWindow is the width of the sliding window of ExpPrecomp.
+const expWindow = 5
+
+// gfP12Powers holds the odd powers a, a³, …, a^(2^expWindow - 1) of a fixed
+// base for ExpPrecomp. It is never modified after it is built, so it can be
+// shared by concurrent exponentiations.
+type gfP12Powers struct {
+	odd [1 << (expWindow - 1)]gfP12
+	// cyclo is set if the base is in the cyclotomic subgroup, where the
+	// cheaper SquareCyclo6 can be used.
+	cyclo bool
+}
+
+// newGfP12Powers returns the table of odd powers of a. If cyclo is set, a MUST
+// be an element of the 6-th cyclotomic group.
+func newGfP12Powers(a *gfP12, cyclo bool) *gfP12Powers {
+	t := &gfP12Powers{cyclo: cyclo}
+	a2 := (&gfP12{}).Square(a)
+	t.odd[0].Set(a)
+	for i := 1; i < len(t.odd); i++ {
+		t.odd[i].Mul(&t.odd[i-1], a2)
+	}
+	return t
+}
+
+// ExpPrecomp sets e to a^power, where t holds the odd powers of a, and then
+// returns e. power must not be negative. It scans power with a sliding window
+// of expWindow bits, so a 256-bit exponent takes about 256 squarings and 43
+// multiplications instead of the 128 multiplications of Exp, and the table,
+// which costs a squaring and 15 multiplications to build, is reused by every
+// exponentiation with the same base. Its running time depends on power.
+func (e *gfP12) ExpPrecomp(t *gfP12Powers, power *big.Int) *gfP12 {
+	sum := (&gfP12{}).SetOne()
+	square := sum.Square
+	if t.cyclo {
+		square = sum.SquareCyclo6
+	}
+
+	started := false
+	for i := power.BitLen() - 1; i >= 0; {
+		if power.Bit(i) == 0 {
+			if started {
+				square(sum)
+			}
+			i--
+			continue
+		}
+
+		// Take the longest window of at most expWindow bits that starts at
+		// bit i and ends with a one.
+		j := i - expWindow + 1
+		if j < 0 {
+			j = 0
+		}
+		for power.Bit(j) == 0 {
+			j++
+		}
+		var d uint
+		for k := i; k >= j; k-- {
+			d = d<<1 | power.Bit(k)
+			if started {
+				square(sum)
+			}
+		}
+		if started {
+			sum.Mul(sum, &t.odd[d>>1])
+		} else {
+			sum.Set(&t.odd[d>>1])
+			started = true
+		}
+		i = j - 1
+	}
+
+	return e.Set(sum)
+}
+
+// ExpCyclo6 sets e to a^power and then returns e. a MUST be an element of the
+// 6-th cyclotomic group, where squarings are done with SquareCyclo6 and
+// inversion is conjugation. The latter makes a signed-digit exponent free, so
+// power is recoded in non-adjacent form, which has a third of its digits
+// non-zero on average instead of half, and negative powers are allowed.
+func (e *gfP12) ExpCyclo6(a *gfP12, power *big.Int) *gfP12 {
+	naf := ComputeNAF(power, 2)
+	if len(naf) == 0 {
+		return e.SetOne()
+	}
+
+	inv := (&gfP12{}).Conjugate(a)
+	sum := &gfP12{}
+	if naf[len(naf)-1] > 0 {
+		sum.Set(a)
+	} else {
+		sum.Set(inv)
+	}
+	for i := len(naf) - 2; i >= 0; i-- {
+		sum.SquareCyclo6(sum)
+		switch {
+		case naf[i] > 0:
+			sum.Mul(sum, a)
+		case naf[i] < 0:
+			sum.Mul(sum, inv)
+		}
+	}
+
+	return e.Set(sum)
+}
+
+// expU64Cyclo6 sets e to a^k. a MUST be an element of the 6-th cyclotomic
+// group.
+func (e *gfP12) expU64Cyclo6(a *gfP12, k uint64) *gfP12 {
+	if k == 0 {
+		return e.SetOne()
+	}
+
+	sum := (&gfP12{}).Set(a)
+	t := &gfP12{}
+
+	for i := bits.Len64(k) - 2; i >= 0; i-- {
+		t.SquareCyclo6(sum)
+		if k>>uint(i)&1 != 0 {
+			sum.Mul(t, a)
+		} else {
+			sum.Set(t)
+		}
+	}
+
+	e.Set(sum)
+	return e
+}
+
 // "New software speed records for cryptographic pairings"
 // Section 3.3, Final exponentiation - 
 // Algorithm 2 Exponentiation by v = 1868033.
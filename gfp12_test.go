@@ -1,6 +1,7 @@
 package bn256
 
 import (
+	"crypto/rand"
 	"math/big"
 	"testing"
 )
@@ -50,6 +51,21 @@ func TestGfp12PowToUCyclo6(t *testing.T) {
 	}
 }
 
+func TestGfP12GenOrder(t *testing.T) {
+	if e := optimalAte(twistGen, curveGen); *e != *gfP12Gen {
+		t.Fatal("gfP12Gen != e(curveGen, twistGen)")
+	}
+
+	// Order is prime, so gfP12Gen has order exactly Order if it isn't one and
+	// its Order-th power is.
+	if gfP12Gen.IsOne() {
+		t.Fatal("gfP12Gen is one")
+	}
+	if !(&gfP12{}).Exp(gfP12Gen, Order).IsOne() {
+		t.Fatal("gfP12Gen^Order != 1")
+	}
+}
+
 func BenchmarkGfp12Square(b *testing.B) {
 	got := &gfP12{}
 	b.ResetTimer()
@@ -59,6 +75,49 @@ func BenchmarkGfp12Square(b *testing.B) {
 	}
 }
 
+func TestGfp12ExpCyclo6(t *testing.T) {
+	one := big.NewInt(1)
+	for i := 0; i < 4; i++ {
+		// in MUST be an element of the 6-th cyclotomic group.
+		_, g, _ := RandomGT(rand.Reader)
+		in := g.p
+
+		random, _ := rand.Int(rand.Reader, Order)
+		for _, k := range []*big.Int{
+			big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(3),
+			new(big.Int).Sub(Order, one), new(big.Int).Lsh(one, 300), random,
+		} {
+			got := (&gfP12{}).ExpCyclo6(in, k)
+			if expected := (&gfP12{}).Exp(in, k); *got != *expected {
+				t.Fatalf("a^%v: not same got=%v, expected=%v", k, got, expected)
+			}
+
+			// a^-k is the inverse of a^k.
+			got.ExpCyclo6(in, new(big.Int).Neg(k))
+			if expected := (&gfP12{}).Exp(in, k); !got.Mul(got, expected).IsOne() {
+				t.Fatalf("a^-%v isn't the inverse of a^%v", k, k)
+			}
+		}
+	}
+}
+
+func BenchmarkGfp12ExpCyclo6(b *testing.B) {
+	_, g, _ := RandomGT(rand.Reader)
+	k, _ := rand.Int(rand.Reader, Order)
+	got := &gfP12{}
+
+	b.Run("Exp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			got.Exp(g.p, k)
+		}
+	})
+	b.Run("ExpCyclo6", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			got.ExpCyclo6(g.p, k)
+		}
+	})
+}
+
 func BenchmarkGfp12SquareCyclo6(b *testing.B) {
 	got := &gfP12{}
 	b.ResetTimer()
@@ -85,3 +144,152 @@ func BenchmarkGfp12PowToUCyclo6(b *testing.B) {
 		got.PowToUCyclo6(gfP12Gen)
 	}
 }
+
+func TestGfp12Equal(t *testing.T) {
+	a := (&gfP12{}).Set(gfP12Gen)
+	if gfp12Equal(a, gfP12Gen) != 1 {
+		t.Fatal("equal elements aren't equal")
+	}
+	coords := []*gfP{
+		&a.x.x.x, &a.x.x.y, &a.x.y.x, &a.x.y.y, &a.x.z.x, &a.x.z.y,
+		&a.y.x.x, &a.y.x.y, &a.y.y.x, &a.y.y.y, &a.y.z.x, &a.y.z.y,
+	}
+	for i, c := range coords {
+		c[0] ^= 1
+		if gfp12Equal(a, gfP12Gen) != 0 {
+			t.Fatalf("elements differing in coordinate %d are equal", i)
+		}
+		c[0] ^= 1
+	}
+}
+
+func TestGfP12CMove(t *testing.T) {
+	src := &vectorSource{seed: "gfP12 cmove"}
+	for i := 0; i < 8; i++ {
+		a, b := src.nextGFp12(), src.nextGFp12()
+		minusA := (&gfP12{}).Neg(a)
+
+		c := &gfP12{}
+		for cond, want := range []*gfP12{a, b} {
+			if c.CMove(a, b, cond); *c != *want {
+				t.Fatalf("CMove(%v, %v, %d) = %v", a, b, cond, c)
+			}
+		}
+		for cond, want := range []*gfP12{a, minusA} {
+			if c.CNeg(a, cond); *c != *want {
+				t.Fatalf("CNeg(%v, %d) = %v", a, cond, c)
+			}
+		}
+		if c.Set(a).CNeg(c, 1); *c != *minusA {
+			t.Fatal("aliased CNeg mismatch")
+		}
+	}
+}
+
+// denseLine returns the line (aτ + b)ω + c as a full element of GF(p¹²).
+func denseLine(a, b, c *gfP2) *gfP12 {
+	l := &gfP12{}
+	l.x.y.Set(a)
+	l.x.z.Set(b)
+	l.y.z.Set(c)
+	return l
+}
+
+func TestGfP12MulLine(t *testing.T) {
+	src := &vectorSource{seed: "gfP12 MulLine"}
+	for i := 0; i < 16; i++ {
+		e := src.nextGFp12()
+		a, b, c := src.nextGFp2(), src.nextGFp2(), src.nextGFp2()
+
+		want := (&gfP12{}).Mul(e, denseLine(a, b, c))
+		if got := (&gfP12{}).Set(e).MulLine(a, b, c); *got != *want {
+			t.Fatalf("MulLine doesn't match Mul for e = %v", e)
+		}
+
+		y, z := src.nextGFp2(), src.nextGFp2()
+		sparse := &gfP6{}
+		sparse.y.Set(y)
+		sparse.z.Set(z)
+		if got, want := (&gfP6{}).mulSparse(&e.x, y, z), (&gfP6{}).Mul(&e.x, sparse); *got != *want {
+			t.Fatalf("mulSparse doesn't match Mul for a = %v", &e.x)
+		}
+	}
+}
+
+// BenchmarkMulLine compares the sparse multiplication by a line of the Miller
+// loop with a full multiplication. BenchmarkPairing shows the effect on the
+// whole pairing.
+func BenchmarkMulLine(b *testing.B) {
+	src := &vectorSource{seed: "gfP12 MulLine"}
+	e := src.nextGFp12()
+	la, lb, lc := src.nextGFp2(), src.nextGFp2(), src.nextGFp2()
+	l := denseLine(la, lb, lc)
+
+	b.Run("Mul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.Mul(e, l)
+		}
+	})
+	b.Run("MulLine", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.MulLine(la, lb, lc)
+		}
+	})
+}
+
+func TestGfp12ExpPrecomp(t *testing.T) {
+	src := &vectorSource{seed: "gfP12 ExpPrecomp"}
+	a := src.nextGFp12()
+	c := src.nextCyclotomic()
+	ta, tc := newGfP12Powers(a, false), newGfP12Powers(c, true)
+
+	powers := []*big.Int{
+		new(big.Int),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(31),
+		big.NewInt(32),
+		big.NewInt(0x8421),
+		new(big.Int).Sub(Order, big.NewInt(1)),
+		new(big.Int).Lsh(big.NewInt(1), 300),
+	}
+	for i := 0; i < 8; i++ {
+		k, _ := rand.Int(rand.Reader, Order)
+		powers = append(powers, k)
+	}
+	for _, k := range powers {
+		if got, want := (&gfP12{}).ExpPrecomp(ta, k), (&gfP12{}).Exp(a, k); *got != *want {
+			t.Fatalf("ExpPrecomp(a, %v) doesn't match Exp", k)
+		}
+		if got, want := (&gfP12{}).ExpPrecomp(tc, k), (&gfP12{}).Exp(c, k); *got != *want {
+			t.Fatalf("ExpPrecomp of a cyclotomic element to %v doesn't match Exp", k)
+		}
+	}
+}
+
+// BenchmarkGfp12ExpPrecomp compares Exp with ExpPrecomp for a 256-bit
+// exponent, with the table built beforehand.
+func BenchmarkGfp12ExpPrecomp(b *testing.B) {
+	src := &vectorSource{seed: "gfP12 ExpPrecomp"}
+	a := src.nextGFp12()
+	k, _ := rand.Int(rand.Reader, Order)
+	k.SetBit(k, 255, 1)
+	e := &gfP12{}
+
+	b.Run("Exp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.Exp(a, k)
+		}
+	})
+	table := newGfP12Powers(a, false)
+	b.Run("ExpPrecomp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ExpPrecomp(table, k)
+		}
+	})
+	b.Run("Table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			newGfP12Powers(a, false)
+		}
+	})
+}
@@ -49,12 +49,19 @@ func (e *gfP2) IsOne() bool {
 	return e.x == zero && e.y == one
 }
 
+// Conjugate sets e to the conjugate of a, -xi+y, and then returns e.
 func (e *gfP2) Conjugate(a *gfP2) *gfP2 {
 	e.y.Set(&a.y)
 	gfpNeg(&e.x, &a.x)
 	return e
 }
 
+// Frobenius sets e to a^p and then returns e. Since p ≡ 3 mod 4, i^p = -i and
+// the Frobenius map of GF(p²) is just the conjugation.
+func (e *gfP2) Frobenius(a *gfP2) *gfP2 {
+	return e.Conjugate(a)
+}
+
 func (e *gfP2) Neg(a *gfP2) *gfP2 {
 	gfpNeg(&e.x, &a.x)
 	gfpNeg(&e.y, &a.y)
@@ -131,6 +138,87 @@ func (e *gfP2) Square(a *gfP2) *gfP2 {
 	return e
 }
 
+// exp sets e to a^bits and then returns e. The sequence of operations only
+// depends on bits, not on a.
+func (e *gfP2) exp(a *gfP2, bits [4]uint64) *gfP2 {
+	sum, power := (&gfP2{}).SetOne(), (&gfP2{}).Set(a)
+	for word := 0; word < 4; word++ {
+		for bit := uint(0); bit < 64; bit++ {
+			if (bits[word]>>bit)&1 == 1 {
+				sum.Mul(sum, power)
+			}
+			power.Square(power)
+		}
+	}
+	return e.Set(sum)
+}
+
+// gfp2CMov sets c to a if cond is 0 and to b if cond is 1, without branching
+// on cond.
+func gfp2CMov(c, a, b *gfP2, cond uint64) {
+	gfpCMov(&c.x, &a.x, &b.x, cond)
+	gfpCMov(&c.y, &a.y, &b.y, cond)
+}
+
+// CMove sets e to a if cond is 0 and to b if cond is 1, and then returns e.
+// cond must be 0 or 1. It doesn't branch on cond; see gfP.CMove.
+func (e *gfP2) CMove(a, b *gfP2, cond int) *gfP2 {
+	gfp2CMov(e, a, b, uint64(cond))
+	return e
+}
+
+// CNeg sets e to a if cond is 0 and to -a if cond is 1, and then returns e.
+// cond must be 0 or 1. It doesn't branch on cond.
+func (e *gfP2) CNeg(a *gfP2, cond int) *gfP2 {
+	neg := (&gfP2{}).Neg(a)
+	gfp2CMov(e, a, neg, uint64(cond))
+	return e
+}
+
+// gfp2Equal returns 1 if a and b are equal and 0 otherwise, without branching
+// on their values.
+func gfp2Equal(a, b *gfP2) uint64 {
+	return gfpEqual(&a.x, &b.x) & gfpEqual(&a.y, &b.y)
+}
+
+// parityCT returns the parity of e: that of its real part, or of its
+// imaginary part if the real part is zero. This is sgn0 of RFC 9380 for
+// GF(p²). It runs in constant time.
+func (e *gfP2) parityCT() uint64 {
+	zero := gfpEqual(&e.y, &gfP{})
+	return parityCT(&e.y) | (zero & parityCT(&e.x))
+}
+
+// sqrtCT sets e to a square root of a and returns 1 if a is a square, or sets
+// e to an unspecified value and returns 0 otherwise, in constant time. It uses
+// algorithm 9 of "Square root computation over even extension fields", Adj
+// and Rodríguez-Henríquez, https://eprint.iacr.org/2012/685, computing both
+// of its branches and selecting the result.
+func (e *gfP2) sqrtCT(a *gfP2) uint64 {
+	minusOne := &gfP2{}
+	gfpNeg(&minusOne.y, newGFp(1))
+
+	a1 := (&gfP2{}).exp(a, pMinus3Over4)
+	x0 := (&gfP2{}).Mul(a1, a)
+	alpha := (&gfP2{}).Mul(a1, x0)
+
+	// If α = -1, the root is i·x0.
+	ix0 := &gfP2{}
+	ix0.x.Set(&x0.y)
+	gfpNeg(&ix0.y, &x0.x)
+
+	// Otherwise it is (1+α)^((p-1)/2)·x0.
+	b := (&gfP2{}).SetOne()
+	b.Add(b, alpha).exp(b, pMinus1Over2).Mul(b, x0)
+
+	x := &gfP2{}
+	gfp2CMov(x, b, ix0, gfp2Equal(alpha, minusOne))
+
+	check := (&gfP2{}).Square(x)
+	e.Set(x)
+	return gfp2Equal(check, a)
+}
+
 func (e *gfP2) Invert(a *gfP2) *gfP2 {
 	// See "Implementing cryptographic pairings", M. Scott, section 3.2.
 	// ftp://136.206.11.249/pub/crypto/pairings.pdf
@@ -148,3 +236,31 @@ func (e *gfP2) Invert(a *gfP2) *gfP2 {
 	gfpMul(&e.y, &a.y, inv)
 	return e
 }
+
+// gfP2BatchInvert sets out[i] to the inverse of in[i] with a single inversion,
+// using Montgomery's trick. Zero elements are left as zero. out and in may be
+// the same slice.
+func gfP2BatchInvert(out, in []gfP2) {
+	// prefix[i] is the product of the non-zero elements of in[:i].
+	prefix := make([]gfP2, len(in)+1)
+	prefix[0].SetOne()
+	for i := range in {
+		if in[i].IsZero() {
+			prefix[i+1].Set(&prefix[i])
+		} else {
+			prefix[i+1].Mul(&prefix[i], &in[i])
+		}
+	}
+
+	inv := (&gfP2{}).Invert(&prefix[len(in)])
+	t := &gfP2{}
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i].IsZero() {
+			out[i].SetZero()
+			continue
+		}
+		t.Mul(inv, &prefix[i])
+		inv.Mul(inv, &in[i])
+		out[i].Set(t)
+	}
+}
@@ -0,0 +1,98 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestGfP2Conjugate(t *testing.T) {
+	src := &vectorSource{seed: "gfP2 conjugate"}
+	for i := 0; i < 16; i++ {
+		a := src.nextGFp2()
+
+		got := (&gfP2{}).Conjugate(a)
+		got.Conjugate(got)
+		if *got != *a {
+			t.Fatalf("conj(conj(a)) != a for a = %v", a)
+		}
+	}
+}
+
+func TestGfP2Frobenius(t *testing.T) {
+	src := &vectorSource{seed: "gfP2 frobenius"}
+	for i := 0; i < 4; i++ {
+		a := src.nextGFp2()
+
+		expected := (&gfP2{}).SetOne()
+		for j := p.BitLen() - 1; j >= 0; j-- {
+			expected.Square(expected)
+			if p.Bit(j) != 0 {
+				expected.Mul(expected, a)
+			}
+		}
+
+		if got := (&gfP2{}).Frobenius(a); *got != *expected {
+			t.Fatalf("not same got=%v, expected=%v", got, expected)
+		}
+	}
+}
+
+func TestGfP2BatchInvert(t *testing.T) {
+	in := make([]gfP2, 16)
+	for i := range in {
+		// Every fifth element, including the first, is zero, and some others
+		// only have one non-zero component.
+		switch i % 5 {
+		case 0:
+		case 1:
+			in[i].x = *togfP(randomGF(rand.Reader))
+		default:
+			in[i].x = *togfP(randomGF(rand.Reader))
+			in[i].y = *togfP(randomGF(rand.Reader))
+		}
+	}
+	out := make([]gfP2, len(in))
+	gfP2BatchInvert(out, in)
+
+	for i := range in {
+		if in[i].IsZero() {
+			if !out[i].IsZero() {
+				t.Fatalf("element %d: inverse of zero is %v", i, &out[i])
+			}
+			continue
+		}
+		if !(&gfP2{}).Mul(&in[i], &out[i]).IsOne() {
+			t.Fatalf("element %d: %v isn't the inverse of %v", i, &out[i], &in[i])
+		}
+	}
+
+	gfP2BatchInvert(in, in)
+	for i := range in {
+		if in[i] != out[i] {
+			t.Fatalf("element %d: in-place inversion doesn't match", i)
+		}
+	}
+}
+
+func TestGfP2CMove(t *testing.T) {
+	src := &vectorSource{seed: "gfP2 cmove"}
+	for i := 0; i < 16; i++ {
+		a, b := src.nextGFp2(), src.nextGFp2()
+		minusA := (&gfP2{}).Neg(a)
+
+		c := &gfP2{}
+		for cond, want := range []*gfP2{a, b} {
+			if c.CMove(a, b, cond); *c != *want {
+				t.Fatalf("CMove(%v, %v, %d) = %v", a, b, cond, c)
+			}
+		}
+		for cond, want := range []*gfP2{a, minusA} {
+			if c.CNeg(a, cond); *c != *want {
+				t.Fatalf("CNeg(%v, %d) = %v", a, cond, c)
+			}
+		}
+		if c.Set(a).CNeg(c, 1); *c != *minusA {
+			t.Fatal("aliased CNeg mismatch")
+		}
+	}
+}
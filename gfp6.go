@@ -121,6 +121,32 @@ func (e *gfP6) Mul(a, b *gfP6) *gfP6 {
 	return e
 }
 
+// mulSparse sets e to a·(yτ + z), the product by an element whose τ²
+// coordinate is zero. It is Mul with the terms of that coordinate left out,
+// which saves one of the six multiplications in GF(p²).
+func (e *gfP6) mulSparse(a *gfP6, y, z *gfP2) *gfP6 {
+	v0 := (&gfP2{}).Mul(&a.z, z)
+	v1 := (&gfP2{}).Mul(&a.y, y)
+
+	t0 := (&gfP2{}).Add(&a.x, &a.y)
+	tz := (&gfP2{}).Mul(t0, y)
+	tz.Sub(tz, v1).MulXi(tz).Add(tz, v0)
+
+	t0.Add(&a.y, &a.z)
+	t1 := (&gfP2{}).Add(y, z)
+	ty := (&gfP2{}).Mul(t0, t1)
+	ty.Sub(ty, v0).Sub(ty, v1)
+
+	t0.Add(&a.x, &a.z)
+	tx := (&gfP2{}).Mul(t0, z)
+	tx.Sub(tx, v0).Add(tx, v1)
+
+	e.x.Set(tx)
+	e.y.Set(ty)
+	e.z.Set(tz)
+	return e
+}
+
 func (e *gfP6) MulScalar(a *gfP6, b *gfP2) *gfP6 {
 	e.x.Mul(&a.x, b)
 	e.y.Mul(&a.y, b)
@@ -135,7 +161,13 @@ func (e *gfP6) MulGFP(a *gfP6, b *gfP) *gfP6 {
 	return e
 }
 
-// MulTau computes τ·(aτ²+bτ+c) = bτ²+cτ+aξ
+// MulTau sets e to τ·a and then returns e. τ is the element 0τ²+1τ+0, a cube
+// root of ξ=i+3 that generates GF(p⁶) over GF(p²), so
+//
+//	τ·(xτ²+yτ+z) = yτ²+zτ+xξ
+//
+// costs one multiplication by ξ instead of a full multiplication. e and a may
+// alias.
 func (e *gfP6) MulTau(a *gfP6) *gfP6 {
 	tz := (&gfP2{}).MulXi(&a.x)
 	ty := (&gfP2{}).Set(&a.y)
@@ -0,0 +1,29 @@
+package bn256
+
+import (
+	"testing"
+)
+
+func TestGfP6MulTau(t *testing.T) {
+	tau := &gfP6{}
+	tau.y.SetOne()
+
+	xi := &gfP6{}
+	xi.z = gfP2{*newGFp(1), *newGFp(3)}
+	if got := (&gfP6{}).Mul(tau, tau); *got.Mul(got, tau) != *xi {
+		t.Fatalf("τ³ = %v, want ξ", got)
+	}
+
+	src := &vectorSource{seed: "gfP6 multau"}
+	for i := 0; i < 16; i++ {
+		a := src.nextGFp6()
+
+		expected := (&gfP6{}).Mul(a, tau)
+		if got := (&gfP6{}).MulTau(a); *got != *expected {
+			t.Fatalf("not same got=%v, expected=%v", got, expected)
+		}
+		if got := (&gfP6{}).Set(a); *got.MulTau(got) != *expected {
+			t.Fatalf("aliased MulTau got=%v, expected=%v", got, expected)
+		}
+	}
+}
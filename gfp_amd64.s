@@ -35,6 +35,7 @@
 
 #include "mul_amd64.h"
 #include "mul_bmi2_amd64.h"
+#include "mul_adx_amd64.h"
 
 TEXT ·gfpNeg(SB),0,$0-16
 	MOVQ ·p2+0(SB), R8
@@ -109,7 +110,18 @@ TEXT ·gfpMul(SB),0,$160-24
 	MOVQ a+8(FP), DI
 	MOVQ b+16(FP), SI
 
-	// Jump to a slightly different implementation if MULX isn't supported.
+	// Use the two carry chains of ADCX and ADOX if they are supported, MULX
+	// alone if only it is, and MULQ otherwise.
+	CMPB ·hasADX(SB), $0
+	JE   noadxMul
+
+	mulADX(0(DI),8(DI),16(DI),24(DI), 0(SI))
+	storeBlock( R8, R9,R10,R11,  0(SP))
+	storeBlock(R12,R13,R14,CX, 32(SP))
+	gfpReduceADX()
+	JMP end
+
+noadxMul:
 	CMPB ·hasBMI2(SB), $0
 	JE   nobmi2Mul
 
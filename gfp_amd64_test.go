@@ -0,0 +1,74 @@
+// +build amd64,!generic
+
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"golang.org/x/sys/cpu"
+)
+
+// gfpMulVariant selects one of the implementations of gfpMul in gfp_amd64.s.
+type gfpMulVariant struct {
+	name            string
+	adx, bmi2       bool
+	supportedOnHost bool
+}
+
+var gfpMulVariants = []gfpMulVariant{
+	{"MULQ", false, false, true},
+	{"MULX", false, true, cpu.X86.HasBMI2},
+	{"ADX", true, true, cpu.X86.HasADX && cpu.X86.HasBMI2},
+}
+
+// useGFpMulVariant makes gfpMul use v until the returned function is called.
+func useGFpMulVariant(v gfpMulVariant) (restore func()) {
+	oldADX, oldBMI2 := hasADX, hasBMI2
+	hasADX, hasBMI2 = v.adx, v.bmi2
+	return func() { hasADX, hasBMI2 = oldADX, oldBMI2 }
+}
+
+func TestGFpMulVariants(t *testing.T) {
+	values := edgeGFp()
+	for i := 0; i < 16; i++ {
+		values = append(values, randomGF(rand.Reader))
+	}
+
+	for _, v := range gfpMulVariants {
+		if !v.supportedOnHost {
+			t.Logf("skipping %s, which this CPU doesn't support", v.name)
+			continue
+		}
+		restore := useGFpMulVariant(v)
+		for _, a := range values {
+			for _, b := range values {
+				got := &gfP{}
+				gfpMul(got, togfP(a), togfP(b))
+				want := new(big.Int).Mul(a, b)
+				want.Mod(want, p)
+				if toBigInt(got).Cmp(want) != 0 || *got != *togfP(want) {
+					restore()
+					t.Fatalf("%s: %v·%v = %v, want %v", v.name, a, b, toBigInt(got), want)
+				}
+			}
+		}
+		restore()
+	}
+}
+
+func BenchmarkGFpMulVariants(b *testing.B) {
+	x, y := togfP(randomGF(rand.Reader)), togfP(randomGF(rand.Reader))
+	for _, v := range gfpMulVariants {
+		if !v.supportedOnHost {
+			continue
+		}
+		b.Run(v.name, func(b *testing.B) {
+			defer useGFpMulVariant(v)()
+			for i := 0; i < b.N; i++ {
+				gfpMul(x, x, y)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// +build amd64,!generic arm64,!generic
+
+package bn256
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+// TestGFpAsmMatchesGeneric checks the assembly implementations of the field
+// operations against the portable ones in gfp_ref.go, word for word, on the
+// edge cases of edgeGFp and on random elements.
+func TestGFpAsmMatchesGeneric(t *testing.T) {
+	var values []*gfP
+	for _, v := range edgeGFp() {
+		values = append(values, togfP(v))
+	}
+	for i := 0; i < 64; i++ {
+		values = append(values, togfP(randomGF(rand.Reader)))
+	}
+
+	for _, a := range values {
+		got, want := &gfP{}, &gfP{}
+		gfpNeg(got, a)
+		gfpNegGeneric(want, a)
+		if *got != *want {
+			t.Fatalf("gfpNeg(%v) = %v, want %v", a, got, want)
+		}
+
+		for _, b := range values {
+			gfpAdd(got, a, b)
+			gfpAddGeneric(want, a, b)
+			if *got != *want {
+				t.Fatalf("gfpAdd(%v, %v) = %v, want %v", a, b, got, want)
+			}
+			gfpSub(got, a, b)
+			gfpSubGeneric(want, a, b)
+			if *got != *want {
+				t.Fatalf("gfpSub(%v, %v) = %v, want %v", a, b, got, want)
+			}
+			gfpMul(got, a, b)
+			gfpMulGeneric(want, a, b)
+			if *got != *want {
+				t.Fatalf("gfpMul(%v, %v) = %v, want %v", a, b, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkGFpMulGeneric(b *testing.B) {
+	x, y := togfP(randomGF(rand.Reader)), togfP(randomGF(rand.Reader))
+	b.Run("asm", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			gfpMul(x, x, y)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			gfpMulGeneric(x, x, y)
+		}
+	})
+}
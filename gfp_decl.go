@@ -11,6 +11,10 @@ import (
 
 var hasBMI2 = cpu.X86.HasBMI2
 
+// hasADX selects the gfpMul variant that uses the ADCX and ADOX instructions
+// along with MULX.
+var hasADX = cpu.X86.HasADX && cpu.X86.HasBMI2
+
 //go:noescape
 func gfpNeg(c, a *gfP)
 
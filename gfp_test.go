@@ -164,3 +164,246 @@ func TestGFp(t *testing.T) {
 		}
 	})
 }
+
+func TestGFpSqrt(t *testing.T) {
+	for i := 0; i < 32; i++ {
+		bigX := randomGF(rand.Reader)
+		x := togfP(bigX)
+		x2 := &gfP{}
+		gfpMul(x2, x, x)
+
+		root, ok := (&gfP{}).Sqrt(x2)
+		if !ok {
+			t.Fatalf("%v² isn't a square", bigX)
+		}
+		neg := &gfP{}
+		gfpNeg(neg, x)
+		if *root != *x && *root != *neg {
+			t.Fatalf("Sqrt(%v²) isn't ±%v", bigX, bigX)
+		}
+		if !x2.IsSquare() {
+			t.Fatalf("IsSquare(%v²) is false", bigX)
+		}
+
+		// -1 is not a square as p ≡ 3 mod 4, so neither is -x².
+		gfpNeg(x2, x2)
+		if bigX.Sign() != 0 {
+			if _, ok := (&gfP{}).Sqrt(x2); ok {
+				t.Fatalf("-%v² is reported to be a square", bigX)
+			}
+			if x2.IsSquare() {
+				t.Fatalf("IsSquare(-%v²) is true", bigX)
+			}
+		}
+	}
+
+	for _, n := range []int64{0, 1, 2, 3, 4, 5, 6, 7} {
+		a := togfP(big.NewInt(n))
+		want := big.Jacobi(big.NewInt(n), p) >= 0
+		if _, ok := (&gfP{}).Sqrt(a); ok != want {
+			t.Errorf("Sqrt(%d) reports %v", n, ok)
+		}
+		if a.IsSquare() != want {
+			t.Errorf("IsSquare(%d) is %v", n, !want)
+		}
+	}
+
+	// Sqrt may alias its argument.
+	a := togfP(big.NewInt(4))
+	if root, ok := a.Sqrt(a); !ok || (toBigInt(root).Cmp(big.NewInt(2)) != 0 && toBigInt(root).Cmp(new(big.Int).Sub(p, big.NewInt(2))) != 0) {
+		t.Fatal("aliased Sqrt(4) isn't ±2")
+	}
+}
+
+// edgeGFp returns field elements that stress the carry propagation and the
+// final conditional subtraction of the field arithmetic.
+func edgeGFp() []*big.Int {
+	one := big.NewInt(1)
+	pow2 := func(n uint) *big.Int { return new(big.Int).Lsh(one, n) }
+
+	ret := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		new(big.Int).Rsh(p, 1),
+		new(big.Int).Add(new(big.Int).Rsh(p, 1), one),
+		pow2(255),
+		new(big.Int).Sub(pow2(256), p),
+		new(big.Int).Sub(p, pow2(256-64-1)),
+	}
+	for i := uint(1); i <= 4; i++ {
+		ret = append(ret, new(big.Int).Sub(p, big.NewInt(int64(i))))
+	}
+	for _, n := range []uint{64, 128, 192} {
+		// Numbers whose low limbs are all ones or all zeros.
+		ret = append(ret, new(big.Int).Sub(pow2(n), one))
+		ret = append(ret, pow2(n))
+		ret = append(ret, new(big.Int).Sub(p, pow2(n)))
+		high := new(big.Int).Rsh(p, n)
+		high.Lsh(high, n)
+		ret = append(ret, high, new(big.Int).Sub(high, one))
+	}
+	for i := 0; i < 16; i++ {
+		k, err := rand.Int(rand.Reader, pow2(64))
+		if err != nil {
+			panic(err)
+		}
+		ret = append(ret, new(big.Int).Sub(p, k.Add(k, one)))
+	}
+	return ret
+}
+
+func TestGFpNearModulus(t *testing.T) {
+	edges := edgeGFp()
+	for _, bigA := range edges {
+		a := togfP(bigA)
+
+		c := &gfP{}
+		gfpNeg(c, a)
+		if got, want := toBigInt(c), new(big.Int).Neg(bigA); got.Cmp(want.Mod(want, p)) != 0 {
+			t.Errorf("-%v: got %v, want %v", bigA, got, want)
+		}
+
+		gfpMul(c, a, a)
+		if got, want := toBigInt(c), new(big.Int).Mul(bigA, bigA); got.Cmp(want.Mod(want, p)) != 0 {
+			t.Errorf("%v²: got %v, want %v", bigA, got, want)
+		}
+
+		for _, bigB := range edges {
+			b := togfP(bigB)
+
+			gfpAdd(c, a, b)
+			if got, want := toBigInt(c), new(big.Int).Add(bigA, bigB); got.Cmp(want.Mod(want, p)) != 0 {
+				t.Errorf("%v+%v: got %v, want %v", bigA, bigB, got, want)
+			}
+
+			gfpSub(c, a, b)
+			if got, want := toBigInt(c), new(big.Int).Sub(bigA, bigB); got.Cmp(want.Mod(want, p)) != 0 {
+				t.Errorf("%v-%v: got %v, want %v", bigA, bigB, got, want)
+			}
+
+			gfpMul(c, a, b)
+			if got, want := toBigInt(c), new(big.Int).Mul(bigA, bigB); got.Cmp(want.Mod(want, p)) != 0 {
+				t.Errorf("%v·%v: got %v, want %v", bigA, bigB, got, want)
+			}
+		}
+	}
+}
+
+// TestGFpReduced checks that the results of the field operations, and of the
+// conversion from and to the Montgomery domain, are always fully reduced.
+func TestGFpReduced(t *testing.T) {
+	pLimbs := gfP(p2)
+	less := func(a *gfP) bool {
+		for i := 3; i >= 0; i-- {
+			if a[i] != pLimbs[i] {
+				return a[i] < pLimbs[i]
+			}
+		}
+		return false
+	}
+
+	edges := edgeGFp()
+	c := &gfP{}
+	for _, bigA := range edges {
+		a := togfP(bigA)
+		if !less(a) {
+			t.Fatalf("Montgomery encoding of %v isn't reduced", bigA)
+		}
+		montDecode(c, a)
+		if !less(c) {
+			t.Fatalf("Montgomery decoding of %v isn't reduced", bigA)
+		}
+
+		for _, bigB := range edges {
+			b := togfP(bigB)
+			for name, op := range map[string]func(c, a, b *gfP){"add": gfpAdd, "sub": gfpSub, "mul": gfpMul} {
+				op(c, a, b)
+				if !less(c) {
+					t.Fatalf("%s(%v, %v) isn't reduced", name, bigA, bigB)
+				}
+			}
+		}
+	}
+
+	// Encoding a 256-bit number that is not less than p must reduce it.
+	a := &gfP{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+	montEncode(c, a)
+	if !less(c) {
+		t.Fatal("Montgomery encoding of 2²⁵⁶-1 isn't reduced")
+	}
+	want := new(big.Int).Lsh(big.NewInt(1), 256)
+	want.Sub(want, big.NewInt(1)).Mod(want, p)
+	if got := toBigInt(c); got.Cmp(want) != 0 {
+		t.Fatalf("Montgomery encoding of 2²⁵⁶-1: got %v, want %v", got, want)
+	}
+}
+
+func TestGFpBatchInvert(t *testing.T) {
+	in := make([]gfP, 16)
+	for i := range in {
+		// Every fifth element, including the first, is zero.
+		if i%5 != 0 {
+			in[i] = *togfP(randomGF(rand.Reader))
+		}
+	}
+	out := make([]gfP, len(in))
+	gfPBatchInvert(out, in)
+
+	one := newGFp(1)
+	for i := range in {
+		if in[i] == (gfP{}) {
+			if out[i] != (gfP{}) {
+				t.Fatalf("element %d: inverse of zero is %v", i, &out[i])
+			}
+			continue
+		}
+		check := &gfP{}
+		gfpMul(check, &in[i], &out[i])
+		if *check != *one {
+			t.Fatalf("element %d: %v isn't the inverse of %v", i, &out[i], &in[i])
+		}
+	}
+
+	gfPBatchInvert(in, in)
+	for i := range in {
+		if in[i] != out[i] {
+			t.Fatalf("element %d: in-place inversion doesn't match", i)
+		}
+	}
+
+	gfPBatchInvert(nil, nil)
+	zeros := make([]gfP, 3)
+	gfPBatchInvert(zeros, zeros)
+	for i := range zeros {
+		if zeros[i] != (gfP{}) {
+			t.Fatal("all-zero input isn't left as zero")
+		}
+	}
+}
+
+func TestGFpCMove(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		a, b := togfP(randomGF(rand.Reader)), togfP(randomGF(rand.Reader))
+		minusA := &gfP{}
+		gfpNeg(minusA, a)
+
+		c := &gfP{}
+		for cond, want := range []*gfP{a, b} {
+			if c.CMove(a, b, cond); *c != *want {
+				t.Fatalf("CMove(%v, %v, %d) = %v", a, b, cond, c)
+			}
+		}
+		for cond, want := range []*gfP{a, minusA} {
+			if c.CNeg(a, cond); *c != *want {
+				t.Fatalf("CNeg(%v, %d) = %v", a, cond, c)
+			}
+		}
+
+		// The result may alias the inputs.
+		c.Set(a)
+		if c.CNeg(c, 1); *c != *minusA {
+			t.Fatal("aliased CNeg mismatch")
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package bn256
+
+import (
+	"math/big"
+)
+
+// GLVBeta is β, a non-trivial cube root of unity in GF(p). The map
+// φ(x, y) = (βx, y) is an endomorphism of the curve, as (βx)³ = x³.
+var GLVBeta = bigFromBase10("4985783334309134261147736404674766913742361673560802634030")
+
+// GLVLambda is λ = 36u³+18u²+6u+1, the cube root of unity modulo Order that
+// is the eigenvalue of φ on G₁ for GLVBeta: φ(P) = λ·P for every P in G₁.
+var GLVLambda = bigFromBase10("9971566668618268521530616648191882281418254099768607949373")
+
+// glvBeta is GLVBeta in Montgomery form.
+var glvBeta = func() *gfP {
+	buf := make([]byte, 32)
+	GLVBeta.FillBytes(buf)
+	ret := &gfP{}
+	ret.Unmarshal(buf)
+	montEncode(ret, ret)
+	return ret
+}()
+
+// Endomorphism sets e to φ(a) = (βx, y), which equals GLVLambda·a, and then
+// returns e. It costs a single field multiplication.
+func (e *G1) Endomorphism(a *G1) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Set(a.p)
+	gfpMul(&e.p.x, &e.p.x, glvBeta)
+	return e
+}
+
+// IsEndomorphismImage reports whether p = φ(base).
+func IsEndomorphismImage(p, base *G1) bool {
+	return new(G1).Endomorphism(base).Equal(p)
+}
+
+// glvBasis is a reduced basis of the lattice of the (a, b) with a+b·λ = 0 mod
+// Order, where λ is GLVLambda: (2u+1, -(6u²+2u)) and (6u²+4u+1, 2u+1). Its
+// determinant is Order and all its entries have at most 128 bits, so that
+// glvDecompose splits a scalar into two halves of about 128 bits.
+var glvBasis = [2][2]*big.Int{
+	{bigFromBase10("13037178982157583875"), bigFromBase10("-254952053719217182009119236802174855688")},
+	{bigFromBase10("254952053719217182022156415784332439563"), bigFromBase10("13037178982157583875")},
+}
+
+// glvDecompose returns k1 and k2 such that k = k1 + k2·GLVLambda mod Order,
+// where k1 and k2 are at most about 128 bits long but may be negative. It
+// subtracts from (k mod Order, 0) the lattice vector closest to it, found by
+// rounding the coordinates of (k, 0) in glvBasis, as in "Faster Point
+// Multiplication on Elliptic Curves with Efficient Endomorphisms", Gallant,
+// Lambert and Vanstone.
+func glvDecompose(k *big.Int) (k1, k2 *big.Int) {
+	k = new(big.Int).Mod(k, Order)
+	half := new(big.Int).Rsh(Order, 1)
+
+	// c1 = round(b2·k/Order), c2 = round(-b1·k/Order). Both numerators are
+	// non-negative.
+	c1 := new(big.Int).Mul(glvBasis[1][1], k)
+	c1.Add(c1, half).Quo(c1, Order)
+	c2 := new(big.Int).Mul(glvBasis[0][1], k)
+	c2.Neg(c2).Add(c2, half).Quo(c2, Order)
+
+	t := new(big.Int)
+	k1 = new(big.Int).Set(k)
+	k1.Sub(k1, t.Mul(c1, glvBasis[0][0]))
+	k1.Sub(k1, t.Mul(c2, glvBasis[1][0]))
+	k2 = new(big.Int).Mul(c1, glvBasis[0][1])
+	k2.Neg(k2).Sub(k2, t.Mul(c2, glvBasis[1][1]))
+	return k1, k2
+}
+
+// mulGLV sets c to scalar·a, where a must be in G₁, by splitting scalar with
+// glvDecompose and computing k1·a + k2·φ(a) with a joint double-and-add, which
+// takes half as many doublings as Mul.
+func (c *curvePoint) mulGLV(a *curvePoint, scalar *big.Int) {
+	k1, k2 := glvDecompose(scalar)
+
+	// table holds a, φ(a) and a+φ(a), negated to match the signs of k1 and
+	// k2.
+	var table [3]curvePoint
+	table[0].Set(a)
+	if k1.Sign() < 0 {
+		table[0].Neg(&table[0])
+		k1.Neg(k1)
+	}
+	table[1].Set(a)
+	gfpMul(&table[1].x, &table[1].x, glvBeta)
+	if k2.Sign() < 0 {
+		table[1].Neg(&table[1])
+		k2.Neg(k2)
+	}
+	table[2].Add(&table[0], &table[1])
+
+	n := k1.BitLen()
+	if k2.BitLen() > n {
+		n = k2.BitLen()
+	}
+
+	sum := &curvePoint{}
+	sum.SetInfinity()
+	for i := n - 1; i >= 0; i-- {
+		sum.Double(sum)
+		switch k1.Bit(i) | k2.Bit(i)<<1 {
+		case 1:
+			sum.Add(sum, &table[0])
+		case 2:
+			sum.Add(sum, &table[1])
+		case 3:
+			sum.Add(sum, &table[2])
+		}
+	}
+
+	c.Set(sum)
+}
+
+// ScalarMultGLV sets e to a*k and then returns e. It returns the same point as
+// ScalarMult for k mod Order, but uses the endomorphism φ to halve the number
+// of doublings, which makes it about 40% faster. Like ScalarMult, its
+// running time depends on k; see ScalarMultCT.
+func (e *G1) ScalarMultGLV(a *G1, k *big.Int) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.mulGLV(a.p, k)
+	return e
+}
+
+// glsBasis is a reduced basis of the lattice of the (a₀, a₁, a₂, a₃) with
+// ∑ aᵢ·μⁱ = 0 mod Order, where μ = 6u² = p mod Order is the eigenvalue of ψ on
+// G₂:
+//
+//	(2u+1, 0, 2u, 1), (2u, u+1, -u, u), (u+1, u, u, -2u), (2u+1, -u, -u-1, -u)
+//
+// Its determinant is -Order and all its entries have at most 64 bits, so that
+// glsDecompose splits a scalar into four parts of about 64 bits.
+var glsBasis = [4][4]*big.Int{
+	{bigFromBase10("13037178982157583875"), bigFromBase10("0"), bigFromBase10("13037178982157583874"), bigFromBase10("1")},
+	{bigFromBase10("13037178982157583874"), bigFromBase10("6518589491078791938"), bigFromBase10("-6518589491078791937"), bigFromBase10("6518589491078791937")},
+	{bigFromBase10("6518589491078791938"), bigFromBase10("6518589491078791937"), bigFromBase10("6518589491078791937"), bigFromBase10("-13037178982157583874")},
+	{bigFromBase10("13037178982157583875"), bigFromBase10("-6518589491078791937"), bigFromBase10("-6518589491078791938"), bigFromBase10("-6518589491078791937")},
+}
+
+// glsRound holds Order times the first row of the inverse of glsBasis, so that
+// the coordinates of (k, 0, 0, 0) in glsBasis are k·glsRound[i]/Order.
+var glsRound = [4]*big.Int{
+	bigFromBase10("1661927778103044753715912134891588971240935301695855419406"),
+	bigFromBase10("1661927778103044753460960081172371789225297475402601771781"),
+	bigFromBase10("13037178982157583875"),
+	bigFromBase10("1661927778103044753715912134891588971234416712204776627469"),
+}
+
+// glsDecompose returns k₀, …, k₃ such that k = ∑ kᵢ·μⁱ mod Order, where μ is
+// the eigenvalue of ψ on G₂, and every kᵢ is at most about 65 bits long but
+// may be negative. It works like glvDecompose, in four dimensions, as in
+// "Endomorphisms for Faster Elliptic Curve Cryptography on a Large Class of
+// Curves", Galbraith, Lin and Scott.
+func glsDecompose(k *big.Int) (ks [4]*big.Int) {
+	k = new(big.Int).Mod(k, Order)
+	half := new(big.Int).Rsh(Order, 1)
+
+	ks[0] = new(big.Int).Set(k)
+	for j := 1; j < 4; j++ {
+		ks[j] = new(big.Int)
+	}
+	c, t := new(big.Int), new(big.Int)
+	for i, row := range glsBasis {
+		c.Mul(k, glsRound[i]).Add(c, half).Quo(c, Order)
+		for j := range ks {
+			ks[j].Sub(ks[j], t.Mul(c, row[j]))
+		}
+	}
+	return ks
+}
+
+// mulGLS sets c to scalar·a, where a must be in G₂, by splitting scalar with
+// glsDecompose and computing ∑ kᵢ·ψⁱ(a) with a joint double-and-add, which
+// takes a quarter as many doublings as Mul.
+func (c *twistPoint) mulGLS(a *twistPoint, scalar *big.Int) {
+	ks := glsDecompose(scalar)
+
+	// table[m] is the sum of the ±ψⁱ(a) for the bits i set in m, with the
+	// signs of the kᵢ.
+	var table [16]twistPoint
+	base := &twistPoint{}
+	base.Set(a)
+	n := 0
+	for i, k := range ks {
+		if i > 0 {
+			base.psi(base)
+		}
+		bit := 1 << uint(i)
+		table[bit].Set(base)
+		if k.Sign() < 0 {
+			table[bit].Neg(&table[bit])
+			k.Neg(k)
+		}
+		for m := 1; m < bit; m++ {
+			table[bit|m].Add(&table[m], &table[bit])
+		}
+		if k.BitLen() > n {
+			n = k.BitLen()
+		}
+	}
+
+	sum := &twistPoint{}
+	sum.SetInfinity()
+	for i := n - 1; i >= 0; i-- {
+		sum.Double(sum)
+		m := ks[0].Bit(i) | ks[1].Bit(i)<<1 | ks[2].Bit(i)<<2 | ks[3].Bit(i)<<3
+		if m != 0 {
+			sum.Add(sum, &table[m])
+		}
+	}
+
+	c.Set(sum)
+}
+
+// ScalarMultGLS sets e to a*k and then returns e. It returns the same point as
+// ScalarMult for k mod Order, but uses the endomorphism ψ to split k into four
+// parts and quarter the number of doublings, which makes it about twice as
+// fast. a MUST be in G₂, where ψ acts as multiplication by 6u²: points of
+// the twist outside of G₂, which Unmarshal accepts, give a wrong result, so
+// check untrusted points with IsInSubGroup or use UnmarshalStrict. For the
+// same reason, ClearCofactor can't use it. Like ScalarMult, its running time
+// depends on k.
+func (e *G2) ScalarMultGLS(a *G2, k *big.Int) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.mulGLS(a.p, k)
+	return e
+}
@@ -0,0 +1,214 @@
+package bn256
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"math/big"
+)
+
+func TestGLVConstants(t *testing.T) {
+	one := big.NewInt(1)
+
+	// β³ = 1 mod p, β != 1.
+	b := new(big.Int).Exp(GLVBeta, big.NewInt(3), p)
+	if b.Cmp(one) != 0 || GLVBeta.Cmp(one) == 0 {
+		t.Fatal("GLVBeta isn't a non-trivial cube root of unity")
+	}
+
+	// λ²+λ+1 = 0 mod Order.
+	l := new(big.Int).Mul(GLVLambda, GLVLambda)
+	l.Add(l, GLVLambda).Add(l, one).Mod(l, Order)
+	if l.Sign() != 0 {
+		t.Fatal("GLVLambda isn't a non-trivial cube root of unity")
+	}
+}
+
+func TestEndomorphism(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG1(rand.Reader)
+	// Keep a in Jacobian form.
+	a.Add(a, b)
+
+	phi := new(G1).Endomorphism(a)
+	if !phi.Equal(new(G1).ScalarMult(a, GLVLambda)) {
+		t.Fatal("φ(P) != λ·P")
+	}
+	if !phi.p.IsOnCurve() {
+		t.Fatal("φ(P) isn't on the curve")
+	}
+	if !IsEndomorphismImage(phi, a) {
+		t.Fatal("φ(P) isn't reported as the image of P")
+	}
+	if IsEndomorphismImage(a, a) || IsEndomorphismImage(phi, b) {
+		t.Fatal("unrelated points are reported as images")
+	}
+
+	inf := new(G1).ScalarBaseMult(Order)
+	if !IsEndomorphismImage(inf, inf) {
+		t.Fatal("φ(∞) != ∞")
+	}
+}
+
+func TestGLVDecompose(t *testing.T) {
+	// The rows of glvBasis are in the lattice and span it.
+	for i, v := range glvBasis {
+		x := new(big.Int).Mul(v[1], GLVLambda)
+		if x.Add(x, v[0]).Mod(x, Order).Sign() != 0 {
+			t.Fatalf("row %d of glvBasis isn't in the lattice", i)
+		}
+	}
+	det := new(big.Int).Mul(glvBasis[0][0], glvBasis[1][1])
+	det.Sub(det, new(big.Int).Mul(glvBasis[0][1], glvBasis[1][0]))
+	if det.Cmp(Order) != 0 {
+		t.Fatal("determinant of glvBasis isn't Order")
+	}
+
+	one := big.NewInt(1)
+	ks := append(ctTestScalars(),
+		new(big.Int).Sub(Order, big.NewInt(2)),
+		new(big.Int).Rsh(Order, 1),
+		new(big.Int).Add(new(big.Int).Rsh(Order, 1), one),
+		GLVLambda,
+		new(big.Int).Sub(Order, GLVLambda),
+	)
+	for _, k := range ks {
+		k1, k2 := glvDecompose(k)
+		if k1.BitLen() > 129 || k2.BitLen() > 129 {
+			t.Fatalf("k = %v: halves of %d and %d bits", k, k1.BitLen(), k2.BitLen())
+		}
+		sum := new(big.Int).Mul(k2, GLVLambda)
+		sum.Add(sum, k1).Sub(sum, k).Mod(sum, Order)
+		if sum.Sign() != 0 {
+			t.Fatalf("k = %v: k1 + k2·λ != k", k)
+		}
+	}
+}
+
+func TestScalarMultGLV(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	one := big.NewInt(1)
+	ks := append(ctTestScalars(),
+		new(big.Int).Sub(Order, big.NewInt(2)),
+		new(big.Int).Rsh(Order, 1),
+		GLVLambda,
+		new(big.Int).Add(GLVLambda, one),
+	)
+	for i := 0; i < 32; i++ {
+		k, _ := rand.Int(rand.Reader, Order)
+		ks = append(ks, k)
+	}
+
+	for _, k := range ks {
+		want := new(G1).ScalarMult(a, new(big.Int).Mod(k, Order))
+		if got := new(G1).ScalarMultGLV(a, k); !got.Equal(want) {
+			t.Fatalf("k = %v: ScalarMultGLV doesn't match ScalarMult", k)
+		}
+	}
+
+	k := ks[len(ks)-1]
+	want := new(G1).ScalarMult(a, k)
+	if a.ScalarMultGLV(a, k); !a.Equal(want) {
+		t.Fatal("aliased ScalarMultGLV doesn't match ScalarMult")
+	}
+}
+
+func BenchmarkScalarMultGLV(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+	_, a, _ := RandomG1(rand.Reader)
+	e := new(G1)
+
+	b.Run("ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ScalarMult(a, k)
+		}
+	})
+	b.Run("ScalarMultGLV", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ScalarMultGLV(a, k)
+		}
+	})
+}
+
+func TestGLSDecompose(t *testing.T) {
+	mu := new(big.Int).Mod(p, Order)
+	if mu.Cmp(sixuSquared) != 0 {
+		t.Fatal("p mod Order isn't 6u²")
+	}
+
+	for i, v := range glsBasis {
+		x, pow := new(big.Int), big.NewInt(1)
+		for _, a := range v {
+			x.Add(x, new(big.Int).Mul(a, pow))
+			pow.Mul(pow, mu).Mod(pow, Order)
+		}
+		if x.Mod(x, Order).Sign() != 0 {
+			t.Fatalf("row %d of glsBasis isn't in the lattice", i)
+		}
+	}
+
+	ks := append(ctTestScalars(),
+		new(big.Int).Sub(Order, big.NewInt(2)),
+		new(big.Int).Rsh(Order, 1),
+		sixuSquared,
+		new(big.Int).Sub(Order, sixuSquared),
+	)
+	for _, k := range ks {
+		parts := glsDecompose(k)
+		sum, pow := new(big.Int), big.NewInt(1)
+		for i, part := range parts {
+			if part.BitLen() > 66 {
+				t.Fatalf("k = %v: part %d has %d bits", k, i, part.BitLen())
+			}
+			sum.Add(sum, new(big.Int).Mul(part, pow))
+			pow.Mul(pow, mu)
+		}
+		if sum.Sub(sum, k).Mod(sum, Order).Sign() != 0 {
+			t.Fatalf("k = %v: ∑ kᵢ·μⁱ != k", k)
+		}
+	}
+}
+
+func TestScalarMultGLS(t *testing.T) {
+	_, a, _ := RandomG2(rand.Reader)
+	ks := append(ctTestScalars(),
+		new(big.Int).Sub(Order, big.NewInt(2)),
+		new(big.Int).Rsh(Order, 1),
+		sixuSquared,
+		new(big.Int).Add(sixuSquared, big.NewInt(1)),
+	)
+	for i := 0; i < 32; i++ {
+		k, _ := rand.Int(rand.Reader, Order)
+		ks = append(ks, k)
+	}
+
+	for _, k := range ks {
+		want := new(G2).ScalarMult(a, new(big.Int).Mod(k, Order))
+		if got := new(G2).ScalarMultGLS(a, k); !got.Equal(want) {
+			t.Fatalf("k = %v: ScalarMultGLS doesn't match ScalarMult", k)
+		}
+	}
+
+	k := ks[len(ks)-1]
+	want := new(G2).ScalarMult(a, k)
+	if a.ScalarMultGLS(a, k); !a.Equal(want) {
+		t.Fatal("aliased ScalarMultGLS doesn't match ScalarMult")
+	}
+}
+
+func BenchmarkScalarMultGLS(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+	_, a, _ := RandomG2(rand.Reader)
+	e := new(G2)
+
+	b.Run("ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ScalarMult(a, k)
+		}
+	})
+	b.Run("ScalarMultGLS", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ScalarMultGLS(a, k)
+		}
+	})
+}
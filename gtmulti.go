@@ -0,0 +1,71 @@
+package bn256
+
+import (
+	"math/big"
+)
+
+// GTProduct returns the product of elems in GT, or one if elems is empty. In
+// the additive notation of this package, it is the sum of elems. It is a
+// convenience for combining pairings that were computed independently; to
+// combine pairings that haven't been computed yet, a product of Miller loops
+// with a single final exponentiation is much faster.
+func GTProduct(elems []*GT) *GT {
+	ret := (&gfP12{}).SetOne()
+	for _, e := range elems {
+		ret.Mul(ret, e.p)
+	}
+	return &GT{ret}
+}
+
+// GTExpProduct returns ∏ elems[i]^exps[i], or one if elems is empty. In the
+// additive notation of this package, it is ∑ exps[i]·elems[i]. Exponents are
+// reduced modulo Order, and elems must be elements of GT, such as pairing
+// results, and not unfinalized Miller loop outputs. It panics if len(elems)
+// != len(exps).
+//
+// The exponentiations share their squarings, so this is faster than
+// exponentiating every element separately.
+func GTExpProduct(elems []*GT, exps []*big.Int) *GT {
+	if len(elems) != len(exps) {
+		panic("bn256: number of exponents doesn't match the number of elements")
+	}
+
+	const window = 4
+	reduced := make([][32]byte, len(exps))
+	// tables[i][d-1] is elems[i]^d.
+	tables := make([][1<<window - 1]gfP12, len(elems))
+	for i, e := range elems {
+		reduced[i] = reduceScalar(exps[i])
+		tables[i][0].Set(e.p)
+		for d := 1; d < len(tables[i]); d++ {
+			tables[i][d].Mul(&tables[i][d-1], e.p)
+		}
+	}
+
+	ret := (&gfP12{}).SetOne()
+	for w := scalarBits/window - 1; w >= 0; w-- {
+		for i := 0; i < window; i++ {
+			ret.SquareCyclo6(ret)
+		}
+		for i := range tables {
+			if d := scalarWindow(&reduced[i], uint(w)*window, window); d != 0 {
+				ret.Mul(ret, &tables[i][d-1])
+			}
+		}
+	}
+	return &GT{ret}
+}
+
+// GTDoubleExp returns g^a · h^b, where g is the generator of GT, in the
+// multiplicative notation of verification equations. In the additive notation
+// of this package, it is a·g + b·h. Exponents are reduced modulo Order, and h
+// must be an element of GT.
+//
+// g^a is computed with the table of powers of g, which has no squarings (see
+// PrecomputeGTGenerator), and h^b with a windowed exponentiation, so the cost
+// is close to that of h^b alone.
+func GTDoubleExp(a *big.Int, h *GT, b *big.Int) *GT {
+	ret := GTExpProduct([]*GT{h}, []*big.Int{b})
+	ret.p.Mul(ret.p, gtBaseExp(&gfP12{}, a))
+	return ret
+}
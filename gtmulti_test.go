@@ -0,0 +1,75 @@
+package bn256
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"math/big"
+)
+
+func TestGTProduct(t *testing.T) {
+	elems := make([]*GT, 3)
+	exps := make([]*big.Int, 3)
+	wantProduct := new(GT).ScalarBaseMult(new(big.Int))
+	wantExpProduct := new(GT).ScalarBaseMult(new(big.Int))
+	for i := range elems {
+		_, elems[i], _ = RandomGT(rand.Reader)
+		exps[i], _ = rand.Int(rand.Reader, Order)
+		wantProduct.Add(wantProduct, elems[i])
+		wantExpProduct.Add(wantExpProduct, new(GT).ScalarMult(elems[i], exps[i]))
+	}
+	// Unreduced and negative exponents are reduced modulo Order.
+	exps[1].Add(exps[1], Order)
+	exps[2].Sub(exps[2], Order)
+
+	if got := GTProduct(elems); *got.p != *wantProduct.p {
+		t.Fatal("GTProduct doesn't match repeated Add")
+	}
+	if got := GTExpProduct(elems, exps); *got.p != *wantExpProduct.p {
+		t.Fatal("GTExpProduct doesn't match ScalarMult and Add")
+	}
+
+	if !GTProduct(nil).p.IsOne() || !GTExpProduct(nil, nil).p.IsOne() {
+		t.Fatal("empty product isn't one")
+	}
+}
+
+func TestGTDoubleExp(t *testing.T) {
+	_, h, _ := RandomGT(rand.Reader)
+	a, _ := rand.Int(rand.Reader, Order)
+	b, _ := rand.Int(rand.Reader, Order)
+
+	for _, c := range [][2]*big.Int{{a, b}, {big.NewInt(0), b}, {a, big.NewInt(0)}, {new(big.Int).Neg(a), new(big.Int).Add(b, Order)}} {
+		want := new(GT).ScalarBaseMult(c[0])
+		want.Add(want, new(GT).ScalarMult(h, new(big.Int).Mod(c[1], Order)))
+		if got := GTDoubleExp(c[0], h, c[1]); *got.p != *want.p {
+			t.Fatalf("GTDoubleExp(%v, h, %v) doesn't match the product of the exponentiations", c[0], c[1])
+		}
+	}
+}
+
+func BenchmarkGTDoubleExp(b *testing.B) {
+	_, h, _ := RandomGT(rand.Reader)
+	x, _ := rand.Int(rand.Reader, Order)
+	y, _ := rand.Int(rand.Reader, Order)
+	PrecomputeGTGenerator()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		GTDoubleExp(x, h, y)
+	}
+}
+
+func BenchmarkGTExpProduct(b *testing.B) {
+	elems := make([]*GT, 8)
+	exps := make([]*big.Int, 8)
+	for i := range elems {
+		_, elems[i], _ = RandomGT(rand.Reader)
+		exps[i], _ = rand.Int(rand.Reader, Order)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		GTExpProduct(elems, exps)
+	}
+}
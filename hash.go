@@ -7,6 +7,96 @@ func HashG1(msg, dst []byte) *G1 {
 	return mapToCurve(hashToBase(msg, dst))
 }
 
+// HashToG1 hashes msg to a point of G₁ whose discrete logarithm is unknown
+// to everyone, as identity-based schemes such as SM9 need. It is HashG1,
+// named to match HashToG2 and HashToGT.
+//
+// msg and dst are hashed to an element t of GF(p), which is mapped to the
+// curve with the map of Fouque and Tibouchi ("Indifferentiable Hashing to
+// Barreto–Naehrig Curves"): the first of three candidate x-coordinates for
+// which x³+3 is a square is used, and the sign of y is that of t. The
+// cofactor of G₁ is one, so every point of the curve is in G₁ and no
+// cofactor clearing is needed.
+func HashToG1(msg, dst []byte) *G1 {
+	return HashG1(msg, dst)
+}
+
+// HashToG2 hashes msg to a point of G₂ whose discrete logarithm is unknown
+// to everyone.
+//
+// msg and dst are hashed to an element t of GF(p²), which is mapped to the
+// twist with the map of HashToG1, whose formulas hold over any field that
+// contains a square root of -3, with b' in place of b. Unlike G₁, the twist
+// has a large cofactor, which is cleared with the ψ-based multiplication of
+// clearCofactor rather than a multiplication by the cofactor itself.
+func HashToG2(msg, dst []byte) *G2 {
+	t := &gfP2{}
+	t.y.Set(hashToBaseComponent(msg, dst, 1))
+	t.x.Set(hashToBaseComponent(msg, dst, 2))
+
+	c := mapToTwist(t)
+	c.clearCofactor(c)
+	c.MakeAffine()
+	return &G2{c}
+}
+
+// mapToTwist maps t to a point of the twist, which is generally not in G₂. See
+// mapToCurve for the derivation.
+func mapToTwist(t *gfP2) *twistPoint {
+	one := (&gfP2{}).SetOne()
+	sqrtMinus3 := &gfP2{y: *s}
+	halfSqrtMinus3MinusOne := &gfP2{y: *sMinus1Over2}
+
+	// w = s·t / (1 + b' + t²), computed as (st)² / w0 with
+	// w0 = st·(1 + b' + t²), so that 1/w² = (1 + b' + t²)⁴ / w0².
+	a := (&gfP2{}).Square(t)
+	a.Add(a, twistB).Add(a, one)
+	st := (&gfP2{}).Mul(sqrtMinus3, t)
+	w0 := (&gfP2{}).Mul(st, a)
+	w0.Invert(w0)
+	w := (&gfP2{}).Square(st)
+	w.Mul(w, w0)
+
+	// x1 = (-1 + s)/2 - t·w.
+	x1 := (&gfP2{}).Mul(t, w)
+	x1.Sub(halfSqrtMinus3MinusOne, x1)
+
+	// x2 = -1 - x1.
+	x2 := (&gfP2{}).Neg(one)
+	x2.Sub(x2, x1)
+
+	// x3 = 1 + 1/w².
+	x3 := (&gfP2{}).Square(a)
+	x3.Square(x3).Mul(x3, w0).Mul(x3, w0).Add(x3, one)
+
+	c := &twistPoint{}
+	y2 := &gfP2{}
+	for _, x := range []*gfP2{x1, x2, x3} {
+		y2.Square(x).Mul(y2, x).Add(y2, twistB)
+		if c.y.sqrtCT(y2) == 1 {
+			c.x.Set(x)
+			break
+		}
+	}
+	if c.y.parityCT() != t.parityCT() {
+		c.y.Neg(&c.y)
+	}
+	c.z.SetOne()
+	c.t.SetOne()
+	return c
+}
+
+// HashToGT hashes msg to an element of GT whose discrete logarithm, with
+// respect to the generator e(g₁, g₂), is unknown to everyone. It is
+// e(HashToG1(msg, dst), g₂).
+//
+// Hashing directly to an element of GF(p¹²) is not a substitute: such an
+// element is almost never in GT, the subgroup of order Order, and using it
+// where a GT element is expected breaks the assumptions of most protocols.
+func HashToGT(msg, dst []byte) *GT {
+	return Pair(HashToG1(msg, dst), &G2{twistGen})
+}
+
 func mapToCurve(t *gfP) *G1 {
 	one := *newGFp(1)
 
@@ -98,3 +188,69 @@ func mapToCurve(t *gfP) *G1 {
 
 	return &G1{cp}
 }
+
+// HashG1ConstantTime is like HashG1, but it runs in time independent of msg
+// (beyond its length), so it can be used on secret inputs such as identities
+// in identity-based encryption. It returns the same point as HashG1.
+//
+// All the candidate x-coordinates of the map are computed, and the quadratic
+// residuosity checks and the choice of the sign of y are done with
+// constant-time selections instead of branches.
+func HashG1ConstantTime(msg, dst []byte) *G1 {
+	return mapToCurveConstantTime(hashToBase(msg, dst))
+}
+
+func mapToCurveConstantTime(t *gfP) *G1 {
+	one := *newGFp(1)
+
+	// See mapToCurve for the derivation of w, x1, x2 and x3.
+	a, t2 := &gfP{}, &gfP{}
+	gfpMul(t2, t, t)
+	gfpAdd(a, curveB, t2)
+	gfpAdd(a, a, &one)
+
+	st := &gfP{}
+	gfpMul(st, s, t)
+
+	w0 := &gfP{}
+	gfpMul(w0, st, a)
+	w0.Invert(w0)
+
+	w := &gfP{}
+	gfpMul(w, st, st)
+	gfpMul(w, w, w0)
+
+	x1, tw := &gfP{}, &gfP{}
+	gfpMul(tw, t, w)
+	gfpSub(x1, sMinus1Over2, tw)
+
+	x2 := newGFp(-1)
+	gfpSub(x2, x2, x1)
+
+	x3 := &gfP{}
+	gfpMul(x3, a, a)
+	gfpMul(x3, x3, x3)
+	gfpMul(x3, x3, w0)
+	gfpMul(x3, x3, w0)
+	gfpAdd(x3, x3, &one)
+
+	// Pick the first x whose x³+3 is a square, starting from the last one
+	// so that the earlier candidates take precedence.
+	x, y := &gfP{}, &gfP{}
+	x.Set(x3)
+	for _, xi := range []*gfP{x2, x1} {
+		gfpMul(y, xi, xi)
+		gfpMul(y, y, xi)
+		gfpAdd(y, y, curveB)
+		gfpCMov(x, x, xi, isSquareCT(y))
+	}
+
+	gfpMul(y, x, x)
+	gfpMul(y, y, x)
+	gfpAdd(y, y, curveB)
+	y.Sqrt(y)
+
+	y.CNeg(y, int(sign0CT(t)^sign0CT(y)))
+
+	return &G1{&curvePoint{x: *x, y: *y, z: one, t: one}}
+}
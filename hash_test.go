@@ -4,6 +4,13 @@ import (
 	"testing"
 
 	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	mathrand "math/rand"
+	"os"
+	"sort"
+	"time"
 )
 
 func TestKnownHashes(t *testing.T) {
@@ -52,3 +59,204 @@ var marshaledHashes = [11][64]byte{
 	[64]byte{45, 115, 123, 118, 162, 144, 82, 134, 198, 17, 162, 200, 91, 168, 191, 115, 31, 66, 81, 201, 111, 250, 133, 16, 247, 62, 92, 251, 227, 234, 116, 183, 16, 117, 103, 177, 94, 201, 169, 155, 59, 218, 174, 242, 28, 66, 171, 113, 245, 247, 98, 236, 193, 26, 85, 62, 215, 101, 229, 214, 191, 153, 176, 168},
 	[64]byte{143, 123, 127, 149, 167, 27, 159, 25, 254, 211, 196, 88, 17, 185, 138, 237, 62, 140, 84, 177, 134, 58, 193, 141, 25, 152, 79, 6, 41, 39, 248, 117, 52, 208, 167, 215, 212, 60, 250, 228, 1, 232, 111, 254, 154, 18, 209, 55, 207, 200, 68, 60, 163, 106, 59, 27, 12, 72, 130, 141, 182, 103, 16, 80},
 }
+
+func TestHashToGT(t *testing.T) {
+	a := HashToGT([]byte("message"), []byte("dst"))
+	if !(&gfP12{}).Exp(a.p, Order).IsOne() {
+		t.Fatal("HashToGT isn't in GT")
+	}
+	if a.p.IsOne() {
+		t.Fatal("HashToGT is one")
+	}
+	if b := HashToGT([]byte("message"), []byte("dst")); *a.p != *b.p {
+		t.Fatal("HashToGT isn't deterministic")
+	}
+	if b := HashToGT([]byte("message"), []byte("other dst")); *a.p == *b.p {
+		t.Fatal("HashToGT ignores dst")
+	}
+}
+
+func TestHashToG1(t *testing.T) {
+	dst := []byte("dst")
+	seen := make(map[string]bool)
+	var parities [2]int
+	for i := 0; i < 256; i++ {
+		msg := []byte{byte(i)}
+		g := HashToG1(msg, dst)
+		if !g.p.IsOnCurve() || g.p.IsInfinity() {
+			t.Fatalf("message %d: point isn't a non-trivial point of the curve", i)
+		}
+		if !bytes.Equal(HashToG1(msg, dst).Marshal(), g.Marshal()) {
+			t.Fatalf("message %d: HashToG1 isn't deterministic", i)
+		}
+		if bytes.Equal(HashToG1(msg, []byte("other dst")).Marshal(), g.Marshal()) {
+			t.Fatalf("message %d: HashToG1 ignores dst", i)
+		}
+
+		m := g.Marshal()
+		if seen[string(m)] {
+			t.Fatalf("message %d: collision", i)
+		}
+		seen[string(m)] = true
+		parities[m[63]&1]++
+	}
+
+	// Both signs of y should come up about equally often.
+	if parities[0] < 96 || parities[1] < 96 {
+		t.Fatalf("skewed parity of y: %v", parities)
+	}
+
+	for i, mh := range marshaledHashes {
+		if !bytes.Equal(HashToG1([]byte{byte(i)}, nil).Marshal(), mh[:]) {
+			t.Fatalf("message %d: doesn't match the known hash", i)
+		}
+	}
+}
+
+func TestHashToG2(t *testing.T) {
+	dst := []byte("BN256G2")
+	vectors := []struct{ msg, compressed string }{
+		{"", "034ce3c351ed372cd4b9e3beed2c99773b739d7a2ce9b299d45e7d39ffa534e6764728861780ac51a00b01abb74fc31c1910297cc780fb5e86e97a5f7ac176f17d"},
+		{"abc", "03245f576c12f4be75fd2c6b4bf265c6f1f1f372bd636c530748b539070f297ac78254348808998fca435da7e7f0540039c7dec5791a08267175af1ee931dd5520"},
+		{"message", "023c1f96f17e4f4bec85da116ef14008d6e10aeb8dbf3ebf02ea28a7b931f8bcf47d730bf06cad14d3c10f740229a52f3a90240d262031e1c290c43827678bce61"},
+	}
+	for _, v := range vectors {
+		got := hex.EncodeToString(HashToG2([]byte(v.msg), dst).MarshalCompressed())
+		if got != v.compressed {
+			t.Errorf("HashToG2(%q) = %s, want %s", v.msg, got, v.compressed)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 64; i++ {
+		msg := []byte{byte(i)}
+		g := HashToG2(msg, dst)
+		if !g.p.IsOnCurve() || g.p.IsInfinity() || !g.IsInSubGroup() {
+			t.Fatalf("message %d: point isn't a non-trivial point of G2", i)
+		}
+		if !bytes.Equal(HashToG2(msg, dst).Marshal(), g.Marshal()) {
+			t.Fatalf("message %d: HashToG2 isn't deterministic", i)
+		}
+		if bytes.Equal(HashToG2(msg, []byte("other dst")).Marshal(), g.Marshal()) {
+			t.Fatalf("message %d: HashToG2 ignores dst", i)
+		}
+		m := g.Marshal()
+		if seen[string(m)] {
+			t.Fatalf("message %d: collision", i)
+		}
+		seen[string(m)] = true
+
+		// The map itself lands on the twist, but only the cofactor
+		// clearing puts the point in G2.
+		tw := &gfP2{}
+		tw.y.Set(hashToBaseComponent(msg, dst, 1))
+		tw.x.Set(hashToBaseComponent(msg, dst, 2))
+		if c := mapToTwist(tw); !c.IsOnCurve() {
+			t.Fatalf("message %d: mapped point isn't on the twist", i)
+		}
+	}
+}
+
+func BenchmarkHashToG2(b *testing.B) {
+	msg := []byte("message")
+	for i := 0; i < b.N; i++ {
+		HashToG2(msg, nil)
+	}
+}
+
+func TestHashG1ConstantTime(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		msg := []byte{byte(i), byte(i >> 8)}
+		want := HashG1(msg, []byte("dst")).Marshal()
+		got := HashG1ConstantTime(msg, []byte("dst"))
+		if !got.p.IsOnCurve() {
+			t.Fatalf("message %d: point isn't on the curve", i)
+		}
+		if !bytes.Equal(got.Marshal(), want) {
+			t.Fatalf("message %d: constant-time hash doesn't match HashG1", i)
+		}
+	}
+}
+
+// usesFirstCandidate reports whether mapToCurve takes its first candidate,
+// x1, for t.
+func usesFirstCandidate(t *gfP) bool {
+	one := newGFp(1)
+	a, st, w := &gfP{}, &gfP{}, &gfP{}
+	gfpMul(a, t, t)
+	gfpAdd(a, a, curveB)
+	gfpAdd(a, a, one)
+	gfpMul(st, s, t)
+	gfpMul(w, st, a)
+	w.Invert(w)
+	gfpMul(w, w, st)
+	gfpMul(w, w, st)
+
+	x1 := &gfP{}
+	gfpMul(x1, t, w)
+	gfpSub(x1, sMinus1Over2, x1)
+	return mapToCurve(t).p.x == *x1
+}
+
+// skipUnlessTimingTests skips t unless BN256_TIMING_TESTS is set. The timing
+// tests measure wall-clock time, so they are only meaningful on an otherwise
+// idle machine and would flake under -race, emulation or a loaded CI runner.
+func skipUnlessTimingTests(t *testing.T) {
+	if os.Getenv("BN256_TIMING_TESTS") == "" {
+		t.Skip("set BN256_TIMING_TESTS=1 to run timing tests")
+	}
+}
+
+// welchT returns Welch's t statistic for the difference between the mean
+// running times of two classes of inputs. It drops the slowest tenth of each
+// class first, which is mostly scheduling noise.
+func welchT(times [2][]float64) float64 {
+	var mean, variance [2]float64
+	for c := range times {
+		sort.Float64s(times[c])
+		times[c] = times[c][:len(times[c])*9/10]
+		for _, v := range times[c] {
+			mean[c] += v
+		}
+		mean[c] /= float64(len(times[c]))
+		for _, v := range times[c] {
+			variance[c] += (v - mean[c]) * (v - mean[c])
+		}
+		variance[c] /= float64(len(times[c]) - 1)
+	}
+	return (mean[0] - mean[1]) / math.Sqrt(variance[0]/float64(len(times[0]))+variance[1]/float64(len(times[1])))
+}
+
+// TestHashG1ConstantTimeTiming compares the running time of
+// HashG1ConstantTime on messages that take the first branch of HashG1 with
+// messages that don't, using Welch's t-test on interleaved measurements.
+func TestHashG1ConstantTimeTiming(t *testing.T) {
+	skipUnlessTimingTests(t)
+
+	dst := []byte("timing")
+	var classes [2][]byte
+	for i := 0; classes[0] == nil || classes[1] == nil; i++ {
+		msg := []byte(fmt.Sprintf("message %d", i))
+		c := 0
+		if !usesFirstCandidate(hashToBase(msg, dst)) {
+			c = 1
+		}
+		if classes[c] == nil {
+			classes[c] = msg
+		}
+	}
+
+	const samples = 4000
+	var times [2][]float64
+	for i := 0; i < 2*samples; i++ {
+		c := mathrand.Intn(2)
+		start := time.Now()
+		HashG1ConstantTime(classes[c], dst)
+		times[c] = append(times[c], float64(time.Since(start)))
+	}
+
+	tStat := welchT(times)
+	if math.Abs(tStat) > 10 {
+		t.Errorf("running time depends on the message: t = %.2f", tStat)
+	}
+}
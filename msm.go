@@ -0,0 +1,257 @@
+package bn256
+
+import (
+	"errors"
+	"math/big"
+)
+
+// scalarBits is the number of bits needed to represent any scalar reduced
+// modulo Order.
+const scalarBits = 256
+
+// reduceScalar returns k mod Order as a 32-byte little-endian value.
+func reduceScalar(k *big.Int) (out [32]byte) {
+	t := k
+	if k.Sign() < 0 || k.Cmp(Order) >= 0 {
+		t = new(big.Int).Mod(k, Order)
+	}
+	var be [32]byte
+	t.FillBytes(be[:])
+	for i := range be {
+		out[i] = be[31-i]
+	}
+	return out
+}
+
+// scalarWindow returns the c-bit window of the little-endian scalar s that
+// starts at bit offset.
+func scalarWindow(s *[32]byte, offset, c uint) uint {
+	var w uint
+	for i := uint(0); i < c; i++ {
+		bit := offset + i
+		if bit >= scalarBits {
+			break
+		}
+		w |= uint(s[bit/8]>>(bit%8)&1) << i
+	}
+	return w
+}
+
+// msmWindowSize picks the Pippenger window size for n points.
+func msmWindowSize(n int) uint {
+	switch {
+	case n < 4:
+		return 2
+	case n < 32:
+		return 3
+	case n < 256:
+		return 5
+	case n < 1024:
+		return 7
+	case n < 8192:
+		return 9
+	default:
+		return 11
+	}
+}
+
+// sumBuckets returns ∑ d·buckets[d] using the running-sum trick, which costs
+// two additions per bucket.
+func sumBuckets(buckets []curvePoint) *curvePoint {
+	running, sum := &curvePoint{}, &curvePoint{}
+	running.SetInfinity()
+	sum.SetInfinity()
+	for d := len(buckets) - 1; d > 0; d-- {
+		running.Add(running, &buckets[d])
+		sum.Add(sum, running)
+	}
+	return sum
+}
+
+// msmG1 computes ∑ scalars[i]·points[i] with the bucket (Pippenger) method.
+// Scalars are reduced modulo Order.
+func msmG1(points []*curvePoint, scalars []*big.Int) *curvePoint {
+	ret := &curvePoint{}
+	ret.SetInfinity()
+	if len(points) == 0 {
+		return ret
+	}
+
+	c := msmWindowSize(len(points))
+	reduced := make([][32]byte, len(scalars))
+	for i, k := range scalars {
+		reduced[i] = reduceScalar(k)
+	}
+
+	buckets := make([]curvePoint, 1<<c)
+	windows := (scalarBits + c - 1) / c
+	for w := int(windows) - 1; w >= 0; w-- {
+		for i := uint(0); i < c; i++ {
+			ret.Double(ret)
+		}
+
+		for i := range buckets {
+			buckets[i].SetInfinity()
+		}
+		for i := range points {
+			d := scalarWindow(&reduced[i], uint(w)*c, c)
+			if d != 0 {
+				buckets[d].Add(&buckets[d], points[i])
+			}
+		}
+		ret.Add(ret, sumBuckets(buckets))
+	}
+
+	return ret
+}
+
+// ScalarPolicy selects how G1MultiScalarMult treats scalars that are not in
+// [0, Order).
+type ScalarPolicy int
+
+const (
+	// ReduceScalars reduces every scalar modulo Order first, so negative and
+	// oversized scalars are accepted. Scalars that are multiples of Order,
+	// including zero, contribute nothing.
+	ReduceScalars ScalarPolicy = iota
+	// RejectUnreducedScalars makes G1MultiScalarMult return an error if any
+	// scalar is negative or not less than Order. This catches callers that
+	// forgot to reduce scalars, which often points at a bug elsewhere.
+	RejectUnreducedScalars
+)
+
+// G1MultiScalarMult returns ∑ scalars[i]·points[i], using the bucket method.
+// Scalars outside of [0, Order) are handled according to policy. It returns
+// an error if the number of scalars doesn't match the number of points. For
+// no points it returns the point at infinity.
+func G1MultiScalarMult(points []*G1, scalars []*big.Int, policy ScalarPolicy) (*G1, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("bn256: number of scalars doesn't match the number of points")
+	}
+	if policy == RejectUnreducedScalars {
+		for _, k := range scalars {
+			if k.Sign() < 0 || k.Cmp(Order) >= 0 {
+				return nil, errors.New("bn256: scalar not reduced modulo Order")
+			}
+		}
+	}
+
+	ps := make([]*curvePoint, len(points))
+	for i, p := range points {
+		ps[i] = p.p
+	}
+	return &G1{msmG1(ps, scalars)}, nil
+}
+
+// MultiScalarMult sets e to ∑ scalars[i]·points[i] and then returns e. It is
+// G1MultiScalarMult with ReduceScalars: scalars are reduced modulo Order, zero
+// scalars contribute nothing and no points give the point at infinity. If the
+// number of scalars doesn't match the number of points, it returns an error
+// and leaves e unchanged.
+func (e *G1) MultiScalarMult(points []*G1, scalars []*big.Int) (*G1, error) {
+	sum, err := G1MultiScalarMult(points, scalars, ReduceScalars)
+	if err != nil {
+		return nil, err
+	}
+	e.p = sum.p
+	return e, nil
+}
+
+// PairMSM returns e(∑ scalars[i]·points[i], q), the shape of the pairings in
+// many SNARK verification equations. The sum is computed with the bucket
+// method on the internal representation and paired directly, without
+// building an intermediate G1. Scalars are reduced modulo Order. It returns an
+// error if the number of scalars doesn't match the number of points.
+func PairMSM(scalars []*big.Int, points []*G1, q *G2) (*GT, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("bn256: number of scalars doesn't match the number of points")
+	}
+
+	ps := make([]*curvePoint, len(points))
+	for i, p := range points {
+		ps[i] = p.p
+	}
+	return &GT{optimalAte(q.p, msmG1(ps, scalars))}, nil
+}
+
+// G1MSMContext holds precomputed tables for a fixed set of G1 points, so that
+// repeated multi-scalar multiplications over the same points (for example a
+// fixed commitment key) don't have to redo the per-point work.
+//
+// For every point P and every window j the context stores 2^(c·j)·P. An
+// evaluation then puts every window digit of every scalar into a single set
+// of buckets, removing all doublings from the evaluation. The table takes
+// len(points)·⌈256/c⌉·128 bytes.
+type G1MSMContext struct {
+	window uint
+	// tables[i][j] is 2^(window·j)·points[i] in affine form.
+	tables [][]curvePoint
+}
+
+// NewG1MSMContext precomputes the tables used by Eval for the given points.
+// The points are copied, so later changes to them don't affect the context.
+func NewG1MSMContext(points []*G1) *G1MSMContext {
+	// With the doublings moved into the precomputation, every point costs
+	// ⌈256/c⌉ additions and the bucket sum costs 2·2^c, so larger windows
+	// pay off sooner than in the stateless method.
+	c := uint(2)
+	best := -1
+	for w := uint(2); w <= 16; w++ {
+		cost := len(points)*int((scalarBits+w-1)/w) + 2<<w
+		if best < 0 || cost < best {
+			best, c = cost, w
+		}
+	}
+
+	ctx := &G1MSMContext{
+		window: c,
+		tables: make([][]curvePoint, len(points)),
+	}
+	windows := (scalarBits + c - 1) / c
+	for i, p := range points {
+		table := make([]curvePoint, windows)
+		table[0].Set(p.p)
+		table[0].MakeAffine()
+		for j := uint(1); j < windows; j++ {
+			table[j].Set(&table[j-1])
+			for k := uint(0); k < c; k++ {
+				table[j].Double(&table[j])
+			}
+			table[j].MakeAffine()
+		}
+		ctx.tables[i] = table
+	}
+
+	return ctx
+}
+
+// Len returns the number of points the context was created with.
+func (c *G1MSMContext) Len() int {
+	return len(c.tables)
+}
+
+// Eval returns ∑ scalars[i]·points[i] for the points the context was created
+// with. Scalars are reduced modulo Order. It panics if the number of scalars
+// doesn't match the number of points.
+func (c *G1MSMContext) Eval(scalars []*big.Int) *G1 {
+	if len(scalars) != len(c.tables) {
+		panic("bn256: number of scalars doesn't match the MSM context")
+	}
+
+	buckets := make([]curvePoint, 1<<c.window)
+	for i := range buckets {
+		buckets[i].SetInfinity()
+	}
+
+	for i, k := range scalars {
+		s := reduceScalar(k)
+		for j := range c.tables[i] {
+			d := scalarWindow(&s, uint(j)*c.window, c.window)
+			if d != 0 {
+				buckets[d].Add(&buckets[d], &c.tables[i][j])
+			}
+		}
+	}
+
+	return &G1{sumBuckets(buckets)}
+}
@@ -0,0 +1,216 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func naiveMSM(points []*G1, scalars []*big.Int) *G1 {
+	sum := new(G1).ScalarBaseMult(big.NewInt(0))
+	for i := range points {
+		sum.Add(sum, new(G1).ScalarMult(points[i], scalars[i]))
+	}
+	return sum
+}
+
+func randomMSMInput(t testing.TB, n int) ([]*G1, []*big.Int) {
+	points := make([]*G1, n)
+	scalars := make([]*big.Int, n)
+	for i := range points {
+		_, p, err := RandomG1(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := rand.Int(rand.Reader, Order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points[i], scalars[i] = p, k
+	}
+	return points, scalars
+}
+
+func TestG1MSMContext(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 17, 64} {
+		points, scalars := randomMSMInput(t, n)
+		if n > 2 {
+			scalars[0] = big.NewInt(0)
+			scalars[1] = new(big.Int).Sub(Order, big.NewInt(1))
+			points[2] = new(G1).ScalarBaseMult(big.NewInt(0))
+		}
+
+		ctx := NewG1MSMContext(points)
+		want := naiveMSM(points, scalars).Marshal()
+		stateless := (&G1{msmG1(g1Points(points), scalars)}).Marshal()
+		if !bytes.Equal(stateless, want) {
+			t.Fatalf("n=%d: stateless MSM doesn't match the naive sum", n)
+		}
+
+		// Evaluate twice to make sure the tables aren't modified.
+		for i := 0; i < 2; i++ {
+			if got := ctx.Eval(scalars).Marshal(); !bytes.Equal(got, stateless) {
+				t.Fatalf("n=%d: context MSM doesn't match stateless MSM", n)
+			}
+		}
+	}
+}
+
+func TestG1MultiScalarMult(t *testing.T) {
+	points, scalars := randomMSMInput(t, 6)
+	scalars[0] = big.NewInt(0)
+	scalars[1] = new(big.Int).Set(Order)
+	scalars[2] = new(big.Int).Add(scalars[2], Order)
+	scalars[3] = new(big.Int).Neg(scalars[3])
+	scalars[4] = new(big.Int).Lsh(scalars[4], 300)
+
+	reduced := make([]*big.Int, len(scalars))
+	for i, k := range scalars {
+		reduced[i] = new(big.Int).Mod(k, Order)
+	}
+	want := naiveMSM(points, reduced)
+
+	got, err := G1MultiScalarMult(points, scalars, ReduceScalars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Fatal("G1MultiScalarMult doesn't match the naive sum")
+	}
+
+	if _, err := G1MultiScalarMult(points, scalars, RejectUnreducedScalars); err == nil {
+		t.Fatal("unreduced scalars were accepted")
+	}
+	for i := 1; i < 5; i++ {
+		mixed := append([]*big.Int{}, reduced...)
+		mixed[i] = scalars[i]
+		if _, err := G1MultiScalarMult(points, mixed, RejectUnreducedScalars); err == nil {
+			t.Fatalf("unreduced scalar %d was accepted", i)
+		}
+	}
+	got, err = G1MultiScalarMult(points, reduced, RejectUnreducedScalars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Fatal("G1MultiScalarMult doesn't match the naive sum for reduced scalars")
+	}
+
+	if _, err := G1MultiScalarMult(points, scalars[1:], ReduceScalars); err == nil {
+		t.Fatal("mismatched lengths were accepted")
+	}
+	got, err = G1MultiScalarMult(nil, nil, RejectUnreducedScalars)
+	if err != nil || !got.p.IsInfinity() {
+		t.Fatal("empty sum isn't the point at infinity")
+	}
+}
+
+func TestG1MultiScalarMultMethod(t *testing.T) {
+	points, scalars := randomMSMInput(t, 5)
+	scalars[0] = big.NewInt(0)
+	want := naiveMSM(points, scalars)
+
+	e := new(G1)
+	got, err := e.MultiScalarMult(points, scalars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != e || !e.Equal(want) {
+		t.Fatal("MultiScalarMult doesn't match the naive sum")
+	}
+
+	if _, err := e.MultiScalarMult(points, scalars[1:]); err == nil {
+		t.Fatal("mismatched lengths were accepted")
+	}
+	if !e.Equal(want) {
+		t.Fatal("failed MultiScalarMult changed its receiver")
+	}
+	if _, err := e.MultiScalarMult(nil, nil); err != nil || !e.p.IsInfinity() {
+		t.Fatal("empty sum isn't the point at infinity")
+	}
+}
+
+func TestPairMSM(t *testing.T) {
+	points, scalars := randomMSMInput(t, 5)
+	_, q, _ := RandomG2(rand.Reader)
+
+	got, err := PairMSM(scalars, points, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Pair(naiveMSM(points, scalars), q); !got.Equal(want) {
+		t.Fatal("PairMSM doesn't match Pair of the naive sum")
+	}
+
+	got, err = PairMSM(nil, nil, q)
+	if err != nil || !got.p.IsOne() {
+		t.Fatal("PairMSM of an empty sum isn't one")
+	}
+	if _, err := PairMSM(scalars[1:], points, q); err == nil {
+		t.Fatal("mismatched lengths were accepted")
+	}
+}
+
+func g1Points(points []*G1) []*curvePoint {
+	ret := make([]*curvePoint, len(points))
+	for i, p := range points {
+		ret[i] = p.p
+	}
+	return ret
+}
+
+func BenchmarkG1MSMContext(b *testing.B) {
+	points, scalars := randomMSMInput(b, 256)
+	ctx := NewG1MSMContext(points)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ctx.Eval(scalars)
+	}
+}
+
+func BenchmarkG1MSMStateless(b *testing.B) {
+	points, scalars := randomMSMInput(b, 256)
+	ps := g1Points(points)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		msmG1(ps, scalars)
+	}
+}
+
+func BenchmarkG1MultiScalarMult(b *testing.B) {
+	points, scalars := randomMSMInput(b, 2048)
+	e := new(G1)
+	b.ResetTimer()
+
+	b.Run("Naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			naiveMSM(points, scalars)
+		}
+	})
+	b.Run("Pippenger", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.MultiScalarMult(points, scalars)
+		}
+	})
+}
+
+func BenchmarkPairMSM(b *testing.B) {
+	points, scalars := randomMSMInput(b, 64)
+	q := &G2{twistGen}
+	b.ResetTimer()
+
+	b.Run("MSMThenPair", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum, _ := G1MultiScalarMult(points, scalars, ReduceScalars)
+			Pair(sum, q)
+		}
+	})
+	b.Run("PairMSM", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PairMSM(scalars, points, q)
+		}
+	})
+}
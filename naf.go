@@ -0,0 +1,161 @@
+package bn256
+
+import (
+	"math/big"
+)
+
+// ComputeNAF returns the width-w non-adjacent form of k, least significant
+// digit first. Every digit is either zero or odd with an absolute value less
+// than 2^(w-1), and any w consecutive digits contain at most one non-zero
+// digit, so that ∑ naf[i]·2^i = k. Negative values of k give negated digits.
+//
+// The result can be passed to ScalarMultNAF any number of times, which avoids
+// recoding the same scalar for every base point. It panics if w is not
+// between 2 and 8.
+func ComputeNAF(k *big.Int, w int) []int8 {
+	if w < 2 || w > 8 {
+		panic("bn256: NAF width out of range")
+	}
+
+	d := new(big.Int).Abs(k)
+	naf := make([]int8, 0, d.BitLen()+1)
+	mask := big.Word(1)<<uint(w) - 1
+	half := int(1) << uint(w-1)
+
+	digit := new(big.Int)
+	for d.Sign() > 0 {
+		var z int
+		if d.Bit(0) == 1 {
+			z = int(d.Bits()[0] & mask)
+			if z >= half {
+				z -= 1 << uint(w)
+			}
+			digit.SetInt64(int64(z))
+			d.Sub(d, digit)
+		}
+		naf = append(naf, int8(z))
+		d.Rsh(d, 1)
+	}
+
+	if k.Sign() < 0 {
+		for i := range naf {
+			naf[i] = -naf[i]
+		}
+	}
+	return naf
+}
+
+// ScalarMultNAF returns p·k, where naf is a non-adjacent form of k as returned
+// by ComputeNAF.
+func ScalarMultNAF(naf []int8, p *G1) *G1 {
+	sum := &curvePoint{}
+	sum.mulNAF(p.p, naf)
+	return &G1{sum}
+}
+
+// mulNAF sets c to k·a, where naf is a non-adjacent form of k. Its table holds
+// the odd multiples of a up to the largest digit of naf.
+func (c *curvePoint) mulNAF(a *curvePoint, naf []int8) {
+	max := 1
+	for _, z := range naf {
+		if int(z) > max {
+			max = int(z)
+		} else if -int(z) > max {
+			max = -int(z)
+		}
+	}
+
+	// odd[i] is (2i+1)·a.
+	odd := make([]curvePoint, max/2+1)
+	odd[0].Set(a)
+	if len(odd) > 1 {
+		double := &curvePoint{}
+		double.Double(a)
+		for i := 1; i < len(odd); i++ {
+			odd[i].Add(&odd[i-1], double)
+		}
+	}
+
+	sum, t := &curvePoint{}, &curvePoint{}
+	sum.SetInfinity()
+	for i := len(naf) - 1; i >= 0; i-- {
+		sum.Double(sum)
+
+		z := naf[i]
+		switch {
+		case z > 0:
+			sum.Add(sum, &odd[z/2])
+		case z < 0:
+			t.Neg(&odd[-z/2])
+			sum.Add(sum, t)
+		}
+	}
+
+	c.Set(sum)
+}
+
+// wnafWindow is the width of the NAF that ScalarMultVartime uses. Its table
+// holds the 2^(wnafWindow-2) = 8 odd multiples P, 3P, …, 15P, and on average
+// one in wnafWindow+1 = 6 digits is non-zero, against one in two bits for
+// double-and-add.
+const wnafWindow = 5
+
+// mulWNAF sets c to scalar·a using the width-wnafWindow NAF of scalar mod
+// Order. Negative digits add the negation of a table entry, which only costs
+// the negation of its y coordinate. Its running time depends on the scalar.
+func (c *curvePoint) mulWNAF(a *curvePoint, scalar *big.Int) {
+	c.mulNAF(a, ComputeNAF(new(big.Int).Mod(scalar, Order), wnafWindow))
+}
+
+// mulWNAF sets c to scalar·a using the width-wnafWindow NAF of scalar mod
+// Order. See the same function for curvePoint.
+func (c *twistPoint) mulWNAF(a *twistPoint, scalar *big.Int) {
+	var odd [1 << (wnafWindow - 2)]twistPoint
+	odd[0].Set(a)
+	double := &twistPoint{}
+	double.Double(a)
+	for i := 1; i < len(odd); i++ {
+		odd[i].Add(&odd[i-1], double)
+	}
+
+	naf := ComputeNAF(new(big.Int).Mod(scalar, Order), wnafWindow)
+	sum, t := &twistPoint{}, &twistPoint{}
+	sum.SetInfinity()
+	for i := len(naf) - 1; i >= 0; i-- {
+		sum.Double(sum)
+
+		z := naf[i]
+		switch {
+		case z > 0:
+			sum.Add(sum, &odd[z/2])
+		case z < 0:
+			t.Neg(&odd[-z/2])
+			sum.Add(sum, t)
+		}
+	}
+
+	c.Set(sum)
+}
+
+// ScalarMultVartime sets e to a*k and then returns e. k is reduced modulo
+// Order. It recodes k into a width-5 NAF, which needs about a third of the
+// additions of ScalarMult, but its running time depends on k: only use it
+// when k is public, for example to verify a proof or a signature.
+func (e *G1) ScalarMultVartime(a *G1, k *big.Int) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.mulWNAF(a.p, k)
+	return e
+}
+
+// ScalarMultVartime sets e to a*k and then returns e. See
+// G1.ScalarMultVartime. k is reduced modulo Order, so a must be in G₂ for the
+// result to match ScalarMult.
+func (e *G2) ScalarMultVartime(a *G2, k *big.Int) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.mulWNAF(a.p, k)
+	return e
+}
@@ -0,0 +1,138 @@
+package bn256
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"math/big"
+)
+
+func TestComputeNAF(t *testing.T) {
+	for w := 2; w <= 8; w++ {
+		for i := 0; i < 8; i++ {
+			k, _ := rand.Int(rand.Reader, Order)
+			if i%2 == 1 {
+				k.Neg(k)
+			}
+
+			naf := ComputeNAF(k, w)
+			sum := new(big.Int)
+			last := len(naf) + w
+			for j := len(naf) - 1; j >= 0; j-- {
+				z := naf[j]
+				sum.Lsh(sum, 1)
+				sum.Add(sum, big.NewInt(int64(z)))
+				if z == 0 {
+					continue
+				}
+				if z%2 == 0 || int(z) >= 1<<uint(w-1) || -int(z) >= 1<<uint(w-1) {
+					t.Fatalf("w=%d: invalid digit %d", w, z)
+				}
+				if last-j < w {
+					t.Fatalf("w=%d: non-zero digits at %d and %d", w, last, j)
+				}
+				last = j
+			}
+			if sum.Cmp(k) != 0 {
+				t.Fatalf("w=%d: NAF evaluates to %v, want %v", w, sum, k)
+			}
+		}
+	}
+
+	if naf := ComputeNAF(new(big.Int), 4); len(naf) != 0 {
+		t.Fatalf("NAF of zero is %v", naf)
+	}
+}
+
+func TestScalarMultNAF(t *testing.T) {
+	_, p, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG1(rand.Reader)
+
+	for w := 2; w <= 8; w++ {
+		k, _ := rand.Int(rand.Reader, Order)
+		naf := ComputeNAF(k, w)
+
+		for _, base := range []*G1{p, q} {
+			want := new(G1).ScalarMult(base, k)
+			if got := ScalarMultNAF(naf, base); !got.Equal(want) {
+				t.Fatalf("w=%d: ScalarMultNAF doesn't match ScalarMult", w)
+			}
+		}
+	}
+
+	if !ScalarMultNAF(nil, p).p.IsInfinity() {
+		t.Fatal("empty NAF doesn't give the point at infinity")
+	}
+}
+
+func TestScalarMultVartime(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	for _, k := range ctTestScalars() {
+		reduced := new(big.Int).Mod(k, Order)
+		want1 := new(G1).ScalarMult(g1, reduced)
+		if got := new(G1).ScalarMultVartime(g1, k); !got.Equal(want1) {
+			t.Errorf("k = %v: G1 mismatch", k)
+		}
+		want2 := new(G2).ScalarMult(g2, reduced)
+		if got := new(G2).ScalarMultVartime(g2, k); !got.Equal(want2) {
+			t.Errorf("k = %v: G2 mismatch", k)
+		}
+	}
+
+	// The point at infinity and aliasing.
+	inf := new(G1).ScalarBaseMult(new(big.Int))
+	if got := new(G1).ScalarMultVartime(inf, big.NewInt(5)); !got.p.IsInfinity() {
+		t.Error("a multiple of the point at infinity isn't the point at infinity")
+	}
+	k, _ := rand.Int(rand.Reader, Order)
+	want := new(G2).ScalarMult(g2, k)
+	if g2.ScalarMultVartime(g2, k); !g2.Equal(want) {
+		t.Error("aliased G2 mismatch")
+	}
+}
+
+// BenchmarkScalarMultVartime compares ScalarMultVartime with ScalarMult. The
+// adds metric is the number of additions after the table is built: the
+// non-zero digits of the NAF, against the set bits for double-and-add.
+func BenchmarkScalarMultVartime(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	e1, e2 := new(G1), new(G2)
+
+	setBits, nonZero := 0, 0
+	for i := 0; i < k.BitLen(); i++ {
+		setBits += int(k.Bit(i))
+	}
+	for _, z := range ComputeNAF(k, wnafWindow) {
+		if z != 0 {
+			nonZero++
+		}
+	}
+
+	b.Run("G1/ScalarMult", func(b *testing.B) {
+		b.ReportMetric(float64(setBits), "adds")
+		for i := 0; i < b.N; i++ {
+			e1.ScalarMult(g1, k)
+		}
+	})
+	b.Run("G1/ScalarMultVartime", func(b *testing.B) {
+		b.ReportMetric(float64(nonZero), "adds")
+		for i := 0; i < b.N; i++ {
+			e1.ScalarMultVartime(g1, k)
+		}
+	})
+	b.Run("G2/ScalarMult", func(b *testing.B) {
+		b.ReportMetric(float64(setBits), "adds")
+		for i := 0; i < b.N; i++ {
+			e2.ScalarMult(g2, k)
+		}
+	})
+	b.Run("G2/ScalarMultVartime", func(b *testing.B) {
+		b.ReportMetric(float64(nonZero), "adds")
+		for i := 0; i < b.N; i++ {
+			e2.ScalarMultVartime(g2, k)
+		}
+	})
+}
@@ -0,0 +1,9 @@
+// +build !bn256debug
+
+package bn256
+
+// debug enables assertions that catch integration mistakes, such as
+// degenerate pairings, and encoding bugs, by decoding the output of every
+// marshal function again, at the cost of some speed. It is set by building
+// with the bn256debug tag.
+const debug = false
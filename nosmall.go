@@ -0,0 +1,6 @@
+// +build !bn256small
+
+package bn256
+
+// smallMemory is false without the bn256small tag; see small.go.
+const smallMemory = false
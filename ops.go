@@ -0,0 +1,71 @@
+package bn256
+
+import (
+	"math/big"
+)
+
+// The functions in this file are allocating counterparts of the in-place
+// methods on G1, G2 and GT. They never modify their arguments and always
+// return a new value, which makes one-off computations in protocol code
+// easier to read. Code that runs in a loop should prefer the methods, which
+// can reuse their receivers.
+
+// AddG1 returns a+b.
+func AddG1(a, b *G1) *G1 {
+	return new(G1).Add(a, b)
+}
+
+// NegG1 returns -a.
+func NegG1(a *G1) *G1 {
+	return new(G1).Neg(a)
+}
+
+// ScalarMultG1 returns p*k.
+func ScalarMultG1(k *big.Int, p *G1) *G1 {
+	return new(G1).ScalarMult(p, k)
+}
+
+// ScalarBaseMultG1 returns g*k where g is the generator of G₁.
+func ScalarBaseMultG1(k *big.Int) *G1 {
+	return new(G1).ScalarBaseMult(k)
+}
+
+// AddG2 returns a+b.
+func AddG2(a, b *G2) *G2 {
+	return new(G2).Add(a, b)
+}
+
+// NegG2 returns -a.
+func NegG2(a *G2) *G2 {
+	return new(G2).Neg(a)
+}
+
+// ScalarMultG2 returns p*k.
+func ScalarMultG2(k *big.Int, p *G2) *G2 {
+	return new(G2).ScalarMult(p, k)
+}
+
+// ScalarBaseMultG2 returns g*k where g is the generator of G₂.
+func ScalarBaseMultG2(k *big.Int) *G2 {
+	return new(G2).ScalarBaseMult(k)
+}
+
+// AddGT returns a+b.
+func AddGT(a, b *GT) *GT {
+	return new(GT).Add(a, b)
+}
+
+// NegGT returns -a.
+func NegGT(a *GT) *GT {
+	return new(GT).Neg(a)
+}
+
+// ScalarMultGT returns p*k.
+func ScalarMultGT(k *big.Int, p *GT) *GT {
+	return new(GT).ScalarMult(p, k)
+}
+
+// ScalarBaseMultGT returns g*k where g is the generator of GT.
+func ScalarBaseMultGT(k *big.Int) *GT {
+	return new(GT).ScalarBaseMult(k)
+}
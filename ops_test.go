@@ -0,0 +1,143 @@
+package bn256
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+)
+
+func TestAllocatingOps(t *testing.T) {
+	k, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG1(rand.Reader)
+	aBytes, bBytes := a.Marshal(), b.Marshal()
+
+	if !AddG1(a, b).Equal(new(G1).Add(a, b)) {
+		t.Error("AddG1 doesn't match G1.Add")
+	}
+	if !NegG1(a).Equal(new(G1).Neg(a)) {
+		t.Error("NegG1 doesn't match G1.Neg")
+	}
+	if !ScalarMultG1(k, b).Equal(new(G1).ScalarMult(b, k)) {
+		t.Error("ScalarMultG1 doesn't match G1.ScalarMult")
+	}
+	if !ScalarBaseMultG1(k).Equal(a) {
+		t.Error("ScalarBaseMultG1 doesn't match G1.ScalarBaseMult")
+	}
+	if !bytes.Equal(a.Marshal(), aBytes) || !bytes.Equal(b.Marshal(), bBytes) {
+		t.Error("G₁ arguments were modified")
+	}
+
+	k, c, _ := RandomG2(rand.Reader)
+	_, d, _ := RandomG2(rand.Reader)
+	cBytes, dBytes := c.Marshal(), d.Marshal()
+
+	if !AddG2(c, d).Equal(new(G2).Add(c, d)) {
+		t.Error("AddG2 doesn't match G2.Add")
+	}
+	if !NegG2(c).Equal(new(G2).Neg(c)) {
+		t.Error("NegG2 doesn't match G2.Neg")
+	}
+	if !ScalarMultG2(k, d).Equal(new(G2).ScalarMult(d, k)) {
+		t.Error("ScalarMultG2 doesn't match G2.ScalarMult")
+	}
+	if !ScalarBaseMultG2(k).Equal(c) {
+		t.Error("ScalarBaseMultG2 doesn't match G2.ScalarBaseMult")
+	}
+	if !bytes.Equal(c.Marshal(), cBytes) || !bytes.Equal(d.Marshal(), dBytes) {
+		t.Error("G₂ arguments were modified")
+	}
+
+	k, e, _ := RandomGT(rand.Reader)
+	_, f, _ := RandomGT(rand.Reader)
+	eBytes, fBytes := e.Marshal(), f.Marshal()
+
+	if *AddGT(e, f).p != *new(GT).Add(e, f).p {
+		t.Error("AddGT doesn't match GT.Add")
+	}
+	if *NegGT(e).p != *new(GT).Neg(e).p {
+		t.Error("NegGT doesn't match GT.Neg")
+	}
+	if *ScalarMultGT(k, f).p != *new(GT).ScalarMult(f, k).p {
+		t.Error("ScalarMultGT doesn't match GT.ScalarMult")
+	}
+	if *ScalarBaseMultGT(k).p != *e.p {
+		t.Error("ScalarBaseMultGT doesn't match GT.ScalarBaseMult")
+	}
+	if !bytes.Equal(e.Marshal(), eBytes) || !bytes.Equal(f.Marshal(), fBytes) {
+		t.Error("GT arguments were modified")
+	}
+}
+
+func TestNegateAll(t *testing.T) {
+	g1s := make([]*G1, 4)
+	g2s := make([]*G2, 4)
+	want1 := make([]*G1, len(g1s))
+	want2 := make([]*G2, len(g2s))
+	for i := range g1s {
+		_, g1s[i], _ = RandomG1(rand.Reader)
+		_, g2s[i], _ = RandomG2(rand.Reader)
+		want1[i] = new(G1).Set(g1s[i])
+		want2[i] = new(G2).Set(g2s[i])
+	}
+
+	NegateAllG1(g1s)
+	NegateAllG2(g2s)
+	for i := range g1s {
+		if !new(G1).Add(g1s[i], want1[i]).p.IsInfinity() {
+			t.Fatalf("G1 point %d wasn't negated", i)
+		}
+		if !new(G2).Add(g2s[i], want2[i]).p.IsInfinity() {
+			t.Fatalf("G2 point %d wasn't negated", i)
+		}
+	}
+
+	NegateAllG1(g1s)
+	NegateAllG2(g2s)
+	for i := range g1s {
+		if !g1s[i].Equal(want1[i]) || !g2s[i].Equal(want2[i]) {
+			t.Fatalf("double negation of point %d isn't the identity", i)
+		}
+	}
+
+	NegateAllG1(nil)
+	NegateAllG2(nil)
+
+	// nil entries and zero values are skipped, and the others still negated.
+	g1s = []*G1{nil, new(G1), new(G1).Set(want1[0])}
+	g2s = []*G2{nil, new(G2), new(G2).Set(want2[0])}
+	NegateAllG1(g1s)
+	NegateAllG2(g2s)
+	if g1s[0] != nil || g1s[1].p != nil || !g1s[2].IsNegationOf(want1[0]) {
+		t.Fatal("NegateAllG1 mishandled nil entries")
+	}
+	if g2s[0] != nil || g2s[1].p != nil || !g2s[2].IsNegationOf(want2[0]) {
+		t.Fatal("NegateAllG2 mishandled nil entries")
+	}
+}
+
+func TestIsNegationOf(t *testing.T) {
+	src := &vectorSource{seed: "is negation of"}
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	neg1 := &G1{scaleCurvePoint(new(G1).Neg(g1).p, src)}
+	neg2 := &G2{scaleTwistPoint(new(G2).Neg(g2).p, src)}
+
+	if !neg1.IsNegationOf(g1) || !g1.IsNegationOf(neg1) {
+		t.Fatal("G1 negation not detected")
+	}
+	if !neg2.IsNegationOf(g2) || !g2.IsNegationOf(neg2) {
+		t.Fatal("G2 negation not detected")
+	}
+	if g1.IsNegationOf(g1) || g2.IsNegationOf(g2) {
+		t.Fatal("point reported to be its own negation")
+	}
+	if g1.IsNegationOf(new(G1).Add(neg1, g1)) || g2.IsNegationOf(new(G2).Add(neg2, g2)) {
+		t.Fatal("point reported to be the negation of the point at infinity")
+	}
+
+	inf1, inf2 := new(G1).ScalarBaseMult(Order), new(G2).ScalarBaseMult(Order)
+	if !inf1.IsNegationOf(inf1) || !inf2.IsNegationOf(inf2) {
+		t.Fatal("the point at infinity isn't its own negation")
+	}
+}
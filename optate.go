@@ -1,5 +1,10 @@
 package bn256
 
+import (
+	"runtime"
+	"sync"
+)
+
 func lineFunctionAdd(r, p *twistPoint, q *curvePoint, r2 *gfP2) (a, b, c *gfP2, rOut *twistPoint) {
 	// See the mixed addition algorithm from "Faster Computation of the
 	// Tate Pairing", http://arxiv.org/pdf/0904.0854v3.pdf
@@ -91,24 +96,25 @@ func lineFunctionDouble(r *twistPoint, q *curvePoint) (a, b, c *gfP2, rOut *twis
 	return
 }
 
-func mulLine(ret *gfP12, a, b, c *gfP2) {
-	a2 := &gfP6{}
-	a2.y.Set(a)
-	a2.z.Set(b)
-	a2.Mul(a2, &ret.x)
-	t3 := (&gfP6{}).MulScalar(&ret.y, c)
+// MulLine sets e to e·((aτ + b)ω + c), the product of the Miller loop
+// accumulator and a line function evaluated at a point of G₁, and then returns
+// e. Three of the six GF(p²) coordinates of a line are zero, so instead of a
+// full Mul this takes two sparse GF(p⁶) multiplications, by aτ + b and by
+// aτ + b + c, and one of GF(p⁶) by the scalar c, combined as in Karatsuba's
+// method. See TestGfP12MulLine and BenchmarkMulLine.
+func (e *gfP12) MulLine(a, b, c *gfP2) *gfP12 {
+	a2 := (&gfP6{}).mulSparse(&e.x, a, b)
+	t3 := (&gfP6{}).MulScalar(&e.y, c)
 
 	t := (&gfP2{}).Add(b, c)
-	t2 := &gfP6{}
-	t2.y.Set(a)
-	t2.z.Set(t)
-	ret.x.Add(&ret.x, &ret.y)
+	e.x.Add(&e.x, &e.y)
 
-	ret.y.Set(t3)
+	e.y.Set(t3)
 
-	ret.x.Mul(&ret.x, t2).Sub(&ret.x, a2).Sub(&ret.x, &ret.y)
+	e.x.mulSparse(&e.x, a, t).Sub(&e.x, a2).Sub(&e.x, &e.y)
 	a2.MulTau(a2)
-	ret.y.Add(&ret.y, a2)
+	e.y.Add(&e.y, a2)
+	return e
 }
 
 // sixuPlus2NAF is 6u+2 in non-adjacent form.
@@ -117,16 +123,35 @@ var sixuPlus2NAF = []int8{0, 0, 0, 1, 0, 0, 0, 0, 0, 1, 0, 0, 1, 0, 0, 0, -1, 0,
 // miller implements the Miller loop for calculating the Optimal Ate pairing.
 // See algorithm 1 from http://cryptojedi.org/papers/dclxvi-20100714.pdf
 func miller(q *twistPoint, p *curvePoint) *gfP12 {
-	ret := (&gfP12{}).SetOne()
+	return multiMiller([]*twistPoint{q}, []*curvePoint{p})
+}
+
+// lineCoeffs holds the coefficients of one line function of the Miller loop,
+// evaluated at the point (1, 1). A line evaluated at a point (x, y) of G₁ then
+// has coefficients a, b·x and c·y, as b only ever depends linearly on x and c
+// on y.
+type lineCoeffs struct {
+	a, b, c gfP2
+}
+
+// precomputeLines returns the line coefficients of the Miller loop for q, in
+// the order in which millerLines uses them. They only depend on q, so the
+// lines of a fixed point can be reused for any number of pairings; see
+// PrecomputedG2.
+func precomputeLines(q *twistPoint) []lineCoeffs {
+	unit := &curvePoint{}
+	unit.x.Set(newGFp(1))
+	unit.y.Set(newGFp(1))
+
+	lines := make([]lineCoeffs, 0, len(sixuPlus2NAF)+2*len(sixuPlus2NAF)/3)
+	appendLine := func(a, b, c *gfP2) {
+		lines = append(lines, lineCoeffs{*a, *b, *c})
+	}
 
 	aAffine := &twistPoint{}
 	aAffine.Set(q)
 	aAffine.MakeAffine()
 
-	bAffine := &curvePoint{}
-	bAffine.Set(p)
-	bAffine.MakeAffine()
-
 	minusA := &twistPoint{}
 	minusA.Neg(aAffine)
 
@@ -136,24 +161,20 @@ func miller(q *twistPoint, p *curvePoint) *gfP12 {
 	r2 := (&gfP2{}).Square(&aAffine.y)
 
 	for i := len(sixuPlus2NAF) - 1; i > 0; i-- {
-		a, b, c, newR := lineFunctionDouble(r, bAffine)
-		if i != len(sixuPlus2NAF)-1 {
-			ret.Square(ret)
-		}
-
-		mulLine(ret, a, b, c)
+		a, b, c, newR := lineFunctionDouble(r, unit)
+		appendLine(a, b, c)
 		r = newR
 
 		switch sixuPlus2NAF[i-1] {
 		case 1:
-			a, b, c, newR = lineFunctionAdd(r, aAffine, bAffine, r2)
+			a, b, c, newR = lineFunctionAdd(r, aAffine, unit, r2)
 		case -1:
-			a, b, c, newR = lineFunctionAdd(r, minusA, bAffine, r2)
+			a, b, c, newR = lineFunctionAdd(r, minusA, unit, r2)
 		default:
 			continue
 		}
 
-		mulLine(ret, a, b, c)
+		appendLine(a, b, c)
 		r = newR
 	}
 
@@ -191,26 +212,138 @@ func miller(q *twistPoint, p *curvePoint) *gfP12 {
 	minusQ2.t.SetOne()
 
 	r2.Square(&q1.y)
-	a, b, c, newR := lineFunctionAdd(r, q1, bAffine, r2)
-	mulLine(ret, a, b, c)
+	a, b, c, newR := lineFunctionAdd(r, q1, unit, r2)
+	appendLine(a, b, c)
 	r = newR
 
 	r2.Square(&minusQ2.y)
-	a, b, c, _ = lineFunctionAdd(r, minusQ2, bAffine, r2)
-	mulLine(ret, a, b, c)
+	a, b, c, _ = lineFunctionAdd(r, minusQ2, unit, r2)
+	appendLine(a, b, c)
+
+	return lines
+}
+
+// millerLines sets ret to the Miller loop for the precomputed lines and the
+// affine point p.
+func millerLines(ret *gfP12, lines []lineCoeffs, p *curvePoint) *gfP12 {
+	return multiMillerLines(ret, [][]lineCoeffs{lines}, []*curvePoint{p})
+}
+
+// multiMillerLines sets ret to the product of the Miller loops for the
+// precomputed lines[i] and the affine points ps[i]. The loops run in
+// lockstep, so the squarings of the accumulator, which are most of the cost
+// of evaluating the lines, are shared by all pairs.
+func multiMillerLines(ret *gfP12, lines [][]lineCoeffs, ps []*curvePoint) *gfP12 {
+	ret.SetOne()
+
+	b, c := &gfP2{}, &gfP2{}
+	eval := func(l *lineCoeffs, p *curvePoint) {
+		b.MulScalar(&l.b, &p.x)
+		c.MulScalar(&l.c, &p.y)
+		ret.MulLine(&l.a, b, c)
+	}
+
+	j := 0
+	for i := len(sixuPlus2NAF) - 1; i > 0; i-- {
+		if i != len(sixuPlus2NAF)-1 {
+			ret.Square(ret)
+		}
+
+		for k, p := range ps {
+			eval(&lines[k][j], p)
+			if sixuPlus2NAF[i-1] != 0 {
+				eval(&lines[k][j+1], p)
+			}
+		}
+		j++
+		if sixuPlus2NAF[i-1] != 0 {
+			j++
+		}
+	}
+	for k, p := range ps {
+		eval(&lines[k][j], p)
+		eval(&lines[k][j+1], p)
+	}
 
 	return ret
 }
 
+// multiMillerChunk is the smallest number of pairs that multiMiller hands to a
+// goroutine of its own. Every chunk repeats the squarings of the accumulator
+// and costs a goroutine, which only pays off once the chunk has a few pairs
+// of its own to precompute lines for.
+const multiMillerChunk = 4
+
+// multiMiller returns the product of the Miller loops of the pairs (qs[i],
+// ps[i]). With enough pairs, it splits them into at most GOMAXPROCS chunks,
+// runs the loops of every chunk on a goroutine of its own with
+// multiMillerSerial and multiplies the results, which are exactly the same
+// as those of a single multiMillerSerial.
+func multiMiller(qs []*twistPoint, ps []*curvePoint) *gfP12 {
+	chunks := runtime.GOMAXPROCS(0)
+	if max := len(qs) / multiMillerChunk; chunks > max {
+		chunks = max
+	}
+	if chunks <= 1 {
+		return multiMillerSerial(qs, ps)
+	}
+
+	results := make([]*gfP12, chunks)
+	var wg sync.WaitGroup
+	for i := range results {
+		lo, hi := i*len(qs)/chunks, (i+1)*len(qs)/chunks
+		wg.Add(1)
+		go func(i, lo, hi int) {
+			defer wg.Done()
+			results[i] = multiMillerSerial(qs[lo:hi], ps[lo:hi])
+		}(i, lo, hi)
+	}
+	wg.Wait()
+
+	ret := results[0]
+	for _, r := range results[1:] {
+		ret.Mul(ret, r)
+	}
+	return ret
+}
+
+// multiMillerSerial returns the product of the Miller loops of the pairs
+// (qs[i], ps[i]) on the calling goroutine. The lines of every q are generated
+// first by precomputeLines and then evaluated at the matching p by
+// multiMillerLines, sharing the squarings of the accumulator. Pairs with a
+// point at infinity contribute one and are skipped. It only reads the points,
+// so concurrent calls may share them.
+func multiMillerSerial(qs []*twistPoint, ps []*curvePoint) *gfP12 {
+	lines := make([][]lineCoeffs, 0, len(qs))
+	points := make([]*curvePoint, 0, len(ps))
+	for i := range qs {
+		if qs[i].IsInfinity() || ps[i].IsInfinity() {
+			continue
+		}
+
+		bAffine := &curvePoint{}
+		bAffine.Set(ps[i])
+		bAffine.MakeAffine()
+
+		lines = append(lines, precomputeLines(qs[i]))
+		points = append(points, bAffine)
+	}
+
+	return multiMillerLines(&gfP12{}, lines, points)
+}
+
 // finalExponentiation computes the (p¹²-1)/Order-th power of an element of
 // GF(p¹²) to obtain an element of GT (steps 13-15 of algorithm 1 from
 // http://cryptojedi.org/papers/dclxvi-20100714.pdf)
 func finalExponentiation(in *gfP12) *gfP12 {
-	t1 := &gfP12{}
+	return finalExponentiationHardPart(finalExponentiationEasyPart(in))
+}
 
-	// This is the p^6-Frobenius
-	t1.x.Neg(&in.x)
-	t1.y.Set(&in.y)
+// finalExponentiationEasyPart computes in^((p⁶-1)(p²+1)), which is an element
+// of the 6-th cyclotomic group.
+func finalExponentiationEasyPart(in *gfP12) *gfP12 {
+	// The conjugation is the p^6-Frobenius.
+	t1 := (&gfP12{}).Conjugate(in)
 
 	inv := &gfP12{}
 	inv.Invert(in)
@@ -219,6 +352,21 @@ func finalExponentiation(in *gfP12) *gfP12 {
 	t2 := (&gfP12{}).FrobeniusP2(t1)
 	t1.Mul(t1, t2) 	// t1 = in^(p^6-1)(p^2+1), where t1 becomes an element of the 6-th cyclotomic group.
 
+	return t1
+}
+
+// finalExponentiationHardPart computes in^((p⁴-p²+1)/Order) for an element in
+// of the 6-th cyclotomic group. It writes the exponent in base p, with digits
+// that are polynomials in u, following "Faster Squaring in the Cyclotomic
+// Subgroup of Sixth Degree Extensions", Granger and Scott, and "Efficient
+// and Secure Implementation of Pairings", Devegili, Scott and Dahab: three
+// exponentiations by u with cyclotomic squarings, the Frobenius maps and a
+// short addition chain replace a 766-bit exponentiation, and the result is
+// exactly in^((p⁴-p²+1)/Order), not a power of it. See
+// TestFinalExponentiationHardPart and BenchmarkFinalExponentiationHardPart.
+func finalExponentiationHardPart(in *gfP12) *gfP12 {
+	t1 := (&gfP12{}).Set(in)
+
 	fp := (&gfP12{}).Frobenius(t1)
 	fp2 := (&gfP12{}).FrobeniusP2(t1)
 	fp3 := (&gfP12{}).Frobenius(fp2)
@@ -262,6 +410,25 @@ func optimalAte(a *twistPoint, b *curvePoint) *gfP12 {
 
 	if a.IsInfinity() || b.IsInfinity() {
 		ret.SetOne()
+	} else if debug {
+		checkNonDegenerate(ret)
 	}
 	return ret
 }
+
+// pairingCheck reports whether ∏ e(a[i], b[i]) is one. It runs a single
+// multiMiller loop and one final exponentiation. Pairs with a point at
+// infinity contribute one.
+func pairingCheck(a []*curvePoint, b []*twistPoint) bool {
+	return finalExponentiation(multiMiller(b, a)).IsOne()
+}
+
+// checkNonDegenerate panics if ret, the pairing of two points that aren't at
+// infinity, is one. The pairing is non-degenerate, so this only happens if the
+// arguments aren't in G₁ and G₂ or the caller made a mistake, such as pairing
+// two points whose combination is known to cancel out.
+func checkNonDegenerate(ret *gfP12) {
+	if ret.IsOne() {
+		panic("bn256: pairing of points not at infinity is one")
+	}
+}
@@ -0,0 +1,62 @@
+package bn256
+
+import (
+	"sync"
+)
+
+// MarshalBuffer holds an encoding produced by one of the MarshalPooled
+// methods. MarshalBuffers are recycled through a pool, so that servers that
+// encode many elements don't allocate a new slice for every one.
+//
+// The contract is: the slice returned by Bytes is only valid until Release is
+// called. After Release, neither the MarshalBuffer nor any slice obtained from
+// it may be used again, as the memory will be handed out to another caller.
+// Release must be called at most once. A MarshalBuffer that is never released
+// is simply garbage collected.
+type MarshalBuffer struct {
+	buf [gtSize]byte
+	n   int
+}
+
+var marshalBufferPool = sync.Pool{
+	New: func() interface{} { return new(MarshalBuffer) },
+}
+
+func getMarshalBuffer() *MarshalBuffer {
+	return marshalBufferPool.Get().(*MarshalBuffer)
+}
+
+// Bytes returns the encoding held by b.
+func (b *MarshalBuffer) Bytes() []byte {
+	return b.buf[:b.n]
+}
+
+// Release returns b to the pool.
+func (b *MarshalBuffer) Release() {
+	b.n = 0
+	marshalBufferPool.Put(b)
+}
+
+// MarshalPooled is like Marshal, but writes the encoding to a pooled buffer.
+// See MarshalBuffer for the rules on using and releasing it.
+func (e *G1) MarshalPooled() *MarshalBuffer {
+	b := getMarshalBuffer()
+	b.n = len(e.marshalTo(b.buf[:g1Size]))
+	return b
+}
+
+// MarshalPooled is like Marshal, but writes the encoding to a pooled buffer.
+// See MarshalBuffer for the rules on using and releasing it.
+func (e *G2) MarshalPooled() *MarshalBuffer {
+	b := getMarshalBuffer()
+	b.n = len(e.marshalTo(b.buf[:g2Size]))
+	return b
+}
+
+// MarshalPooled is like Marshal, but writes the encoding to a pooled buffer.
+// See MarshalBuffer for the rules on using and releasing it.
+func (e *GT) MarshalPooled() *MarshalBuffer {
+	b := getMarshalBuffer()
+	b.n = len(e.marshalTo(b.buf[:gtSize]))
+	return b
+}
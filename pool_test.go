@@ -0,0 +1,57 @@
+package bn256
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+)
+
+func TestMarshalPooled(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	_, gt, _ := RandomGT(rand.Reader)
+
+	for i := 0; i < 2; i++ {
+		for _, c := range []struct {
+			name   string
+			pooled *MarshalBuffer
+			want   []byte
+		}{
+			{"G1", g1.MarshalPooled(), g1.Marshal()},
+			{"G2", g2.MarshalPooled(), g2.Marshal()},
+			{"GT", gt.MarshalPooled(), gt.Marshal()},
+			{"G1 infinity", new(G1).ScalarBaseMult(Order).MarshalPooled(), new(G1).ScalarBaseMult(Order).Marshal()},
+			{"G2 infinity", new(G2).ScalarBaseMult(Order).MarshalPooled(), new(G2).ScalarBaseMult(Order).Marshal()},
+		} {
+			if !bytes.Equal(c.pooled.Bytes(), c.want) {
+				t.Errorf("%s: MarshalPooled doesn't match Marshal", c.name)
+			}
+			c.pooled.Release()
+		}
+	}
+}
+
+var marshalSink []byte
+
+func BenchmarkG1Marshal(b *testing.B) {
+	_, g1, _ := RandomG1(rand.Reader)
+	g1.Marshal()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		marshalSink = g1.Marshal()
+	}
+}
+
+func BenchmarkG1MarshalPooled(b *testing.B) {
+	_, g1, _ := RandomG1(rand.Reader)
+	g1.Marshal()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g1.MarshalPooled().Release()
+	}
+}
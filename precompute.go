@@ -0,0 +1,117 @@
+package bn256
+
+import (
+	"math/big"
+	"sync"
+)
+
+// PrecomputedG2 holds the line functions of the Miller loop for a fixed G₂
+// point, so that pairings with that point only do the work that depends on
+// the G₁ point. It is safe for concurrent use.
+type PrecomputedG2 struct {
+	infinity bool
+	lines    []lineCoeffs
+}
+
+// PrecomputeG2 returns the precomputed form of q.
+func PrecomputeG2(q *G2) *PrecomputedG2 {
+	if q.p.IsInfinity() {
+		return &PrecomputedG2{infinity: true}
+	}
+	return &PrecomputedG2{lines: precomputeLines(q.p)}
+}
+
+// pair sets ret to e(p, q), using affine as scratch space.
+func (q *PrecomputedG2) pair(ret *gfP12, affine *curvePoint, p *curvePoint) *gfP12 {
+	if q.infinity || p.IsInfinity() {
+		return ret.SetOne()
+	}
+	affine.Set(p)
+	affine.MakeAffine()
+	millerLines(ret, q.lines, affine)
+	ret.Set(finalExponentiation(ret))
+	if debug {
+		checkNonDegenerate(ret)
+	}
+	return ret
+}
+
+// Pair returns e(g1, q).
+func (q *PrecomputedG2) Pair(g1 *G1) *GT {
+	return &GT{q.pair(&gfP12{}, &curvePoint{}, g1.p)}
+}
+
+// PairMany returns e(g1s[i], q) for every point in g1s.
+func (q *PrecomputedG2) PairMany(g1s []*G1) []*GT {
+	out := make([]*GT, len(g1s))
+	affine := &curvePoint{}
+	for i, g1 := range g1s {
+		out[i] = &GT{q.pair(&gfP12{}, affine, g1.p)}
+	}
+	return out
+}
+
+// g2GenLines holds the line functions for the generator of G₂. It is built on
+// the first call to PairWithGenerator and never modified afterwards.
+var (
+	g2GenLinesOnce sync.Once
+	g2GenLines     *PrecomputedG2
+)
+
+// PairWithGenerator returns e(g1, g₂), where g₂ is the generator of G₂. It is
+// the same as Pair(g1, new(G2).ScalarBaseMult(big.NewInt(1))), but it pairs
+// against line functions for g₂ that are computed on the first call and
+// shared by all later ones, so repeated calls only do the work that depends
+// on g1; see BenchmarkPairWithGenerator. It is safe for concurrent use.
+//
+// When built with the bn256small tag the lines are never stored and
+// PairWithGenerator is just Pair.
+func PairWithGenerator(g1 *G1) *GT {
+	if smallMemory {
+		return Pair(g1, &G2{twistGen})
+	}
+	g2GenLinesOnce.Do(func() {
+		g2GenLines = PrecomputeG2(&G2{twistGen})
+	})
+	return g2GenLines.Pair(g1)
+}
+
+// G2Pairer pairs a stream of G₁ points against one precomputed G₂ point, such
+// as a stream of signatures against a single public key. It reuses its
+// scratch space between calls, so it is not safe for concurrent use; create
+// one per goroutine from a shared PrecomputedG2 instead.
+type G2Pairer struct {
+	q      *PrecomputedG2
+	affine curvePoint
+}
+
+// NewG2Pairer returns a G2Pairer for q. The precomputation is shared, not
+// copied.
+func NewG2Pairer(q *PrecomputedG2) *G2Pairer {
+	return &G2Pairer{q: q}
+}
+
+// Pair returns e(g1, q), where q is the point the pairer was created for.
+func (s *G2Pairer) Pair(g1 *G1) *GT {
+	return &GT{s.q.pair(&gfP12{}, &s.affine, g1.p)}
+}
+
+// PrecomputedGT holds a table of powers of a fixed element of GT, so that
+// exponentiations of that element, such as the public values of a
+// verification equation, need fewer multiplications. It is safe for
+// concurrent use.
+type PrecomputedGT struct {
+	powers *gfP12Powers
+}
+
+// PrecomputeGT returns the precomputed form of a, which must be an element of
+// GT, such as a pairing result.
+func PrecomputeGT(a *GT) *PrecomputedGT {
+	return &PrecomputedGT{newGfP12Powers(a.p, true)}
+}
+
+// Exp returns a^k, where a is the precomputed element, like GT.Exp. k is
+// reduced modulo Order. Its running time depends on k.
+func (q *PrecomputedGT) Exp(k *big.Int) *GT {
+	return &GT{(&gfP12{}).ExpPrecomp(q.powers, modOrder(k))}
+}
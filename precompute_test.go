@@ -0,0 +1,154 @@
+package bn256
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"crypto/rand"
+)
+
+func TestPrecomputedG2(t *testing.T) {
+	_, q, _ := RandomG2(rand.Reader)
+	prec := PrecomputeG2(q)
+	pairer := NewG2Pairer(prec)
+
+	g1s := make([]*G1, 4)
+	for i := range g1s {
+		_, g1s[i], _ = RandomG1(rand.Reader)
+	}
+	g1s = append(g1s, new(G1).ScalarBaseMult(Order))
+
+	many := prec.PairMany(g1s)
+	for i, g1 := range g1s {
+		want := Pair(g1, q)
+		if *prec.Pair(g1).p != *want.p {
+			t.Fatalf("point %d: PrecomputedG2.Pair doesn't match Pair", i)
+		}
+		if *many[i].p != *want.p {
+			t.Fatalf("point %d: PrecomputedG2.PairMany doesn't match Pair", i)
+		}
+		if *pairer.Pair(g1).p != *want.p {
+			t.Fatalf("point %d: G2Pairer.Pair doesn't match Pair", i)
+		}
+	}
+
+	inf := PrecomputeG2(new(G2).ScalarBaseMult(Order))
+	if !inf.Pair(g1s[0]).p.IsOne() {
+		t.Fatal("pairing with the precomputed point at infinity isn't one")
+	}
+}
+
+func TestMultiMillerLines(t *testing.T) {
+	lines := make([][]lineCoeffs, 3)
+	ps := make([]*curvePoint, len(lines))
+	want := (&gfP12{}).SetOne()
+	for i := range lines {
+		_, g1, _ := RandomG1(rand.Reader)
+		_, g2, _ := RandomG2(rand.Reader)
+		lines[i] = precomputeLines(g2.p)
+		ps[i] = g1.p
+		ps[i].MakeAffine()
+		want.Mul(want, millerLines(&gfP12{}, lines[i], ps[i]))
+	}
+
+	// The loops share their squarings, so the product is exact before the
+	// final exponentiation too.
+	if got := multiMillerLines(&gfP12{}, lines, ps); *got != *want {
+		t.Fatal("multiMillerLines doesn't match the product of millerLines")
+	}
+	if got := multiMillerLines(&gfP12{}, nil, nil); !got.IsOne() {
+		t.Fatal("multiMillerLines of no pairs isn't one")
+	}
+}
+
+func BenchmarkPrecomputedG2Pair(b *testing.B) {
+	_, q, _ := RandomG2(rand.Reader)
+	_, p, _ := RandomG1(rand.Reader)
+	pairer := NewG2Pairer(PrecomputeG2(q))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pairer.Pair(p)
+	}
+}
+
+func TestPairWithGenerator(t *testing.T) {
+	g2 := new(G2).ScalarBaseMult(big.NewInt(1))
+	g1s := make([]*G1, 4)
+	for i := range g1s {
+		_, g1s[i], _ = RandomG1(rand.Reader)
+	}
+	g1s = append(g1s, new(G1).ScalarBaseMult(Order))
+
+	// The first calls race to build the shared lines.
+	var wg sync.WaitGroup
+	got := make([]*GT, len(g1s))
+	for i := range g1s {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = PairWithGenerator(g1s[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, g1 := range g1s {
+		if want := Pair(g1, g2); !got[i].Equal(want) {
+			t.Fatalf("point %d: PairWithGenerator doesn't match Pair", i)
+		}
+		if !PairWithGenerator(g1).Equal(got[i]) {
+			t.Fatalf("point %d: repeated PairWithGenerator differs", i)
+		}
+	}
+}
+
+// BenchmarkPairWithGenerator compares repeated pairings against the generator
+// of G₂ with and without the shared precomputed lines.
+func BenchmarkPairWithGenerator(b *testing.B) {
+	g2 := new(G2).ScalarBaseMult(big.NewInt(1))
+	_, p, _ := RandomG1(rand.Reader)
+
+	b.Run("Pair", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Pair(p, g2)
+		}
+	})
+	PairWithGenerator(p)
+	b.Run("PairWithGenerator", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PairWithGenerator(p)
+		}
+	})
+}
+
+func TestPrecomputedGT(t *testing.T) {
+	_, g, _ := RandomGT(rand.Reader)
+	prec := PrecomputeGT(g)
+	k, _ := rand.Int(rand.Reader, Order)
+	for _, k := range []*big.Int{new(big.Int), big.NewInt(1), big.NewInt(-5), k, new(big.Int).Add(k, Order)} {
+		if got, want := prec.Exp(k), new(GT).Exp(g, k); !got.Equal(want) {
+			t.Fatalf("PrecomputedGT.Exp(%v) doesn't match Exp", k)
+		}
+	}
+}
+
+// BenchmarkPrecomputedGTExp compares repeated exponentiations of the same
+// element of GT with and without the table of powers.
+func BenchmarkPrecomputedGTExp(b *testing.B) {
+	_, g, _ := RandomGT(rand.Reader)
+	k, _ := rand.Int(rand.Reader, Order)
+
+	b.Run("Exp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			new(GT).Exp(g, k)
+		}
+	})
+	prec := PrecomputeGT(g)
+	b.Run("PrecomputedGT", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			prec.Exp(k)
+		}
+	})
+}
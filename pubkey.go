@@ -0,0 +1,51 @@
+package bn256
+
+import (
+	"errors"
+)
+
+// RejectIdentityG1 returns an error if pk is the point at infinity. Public
+// keys and other points that a protocol multiplies by a secret must never be
+// the identity: a signature under the identity is the identity for every
+// message, so it can be forged without any secret. Protocols should call it,
+// or use UnmarshalPublicKey, on every point they import.
+func RejectIdentityG1(pk *G1) error {
+	if pk.p == nil || pk.p.IsInfinity() {
+		return errors.New("bn256: public key is the point at infinity")
+	}
+	return nil
+}
+
+// RejectIdentityG2 is the G₂ counterpart of RejectIdentityG1.
+func RejectIdentityG2(pk *G2) error {
+	if pk.p == nil || pk.p.IsInfinity() {
+		return errors.New("bn256: public key is the point at infinity")
+	}
+	return nil
+}
+
+// UnmarshalPublicKey is like Unmarshal, but also returns an error if the point
+// is the point at infinity.
+func (e *G1) UnmarshalPublicKey(m []byte) ([]byte, error) {
+	rest, err := e.Unmarshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := RejectIdentityG1(e); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
+// UnmarshalPublicKey is like Unmarshal, but also returns an error if the point
+// is the point at infinity or not in G₂.
+func (e *G2) UnmarshalPublicKey(m []byte) ([]byte, error) {
+	rest, err := e.UnmarshalStrict(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := RejectIdentityG2(e); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
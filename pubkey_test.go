@@ -0,0 +1,55 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestRejectIdentity(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	inf1 := new(G1).ScalarBaseMult(new(big.Int))
+	inf2 := new(G2).ScalarBaseMult(new(big.Int))
+
+	if err := RejectIdentityG1(g1); err != nil {
+		t.Error(err)
+	}
+	if err := RejectIdentityG2(g2); err != nil {
+		t.Error(err)
+	}
+	if RejectIdentityG1(inf1) == nil || RejectIdentityG1(new(G1)) == nil {
+		t.Error("identity of G1 accepted")
+	}
+	if RejectIdentityG2(inf2) == nil || RejectIdentityG2(new(G2)) == nil {
+		t.Error("identity of G2 accepted")
+	}
+}
+
+func TestUnmarshalPublicKey(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+
+	got1 := new(G1)
+	if _, err := got1.UnmarshalPublicKey(g1.Marshal()); err != nil || !got1.Equal(g1) {
+		t.Errorf("G1 public key: %v", err)
+	}
+	got2 := new(G2)
+	if _, err := got2.UnmarshalPublicKey(g2.Marshal()); err != nil || !got2.Equal(g2) {
+		t.Errorf("G2 public key: %v", err)
+	}
+
+	inf1 := new(G1).ScalarBaseMult(new(big.Int)).Marshal()
+	if _, err := new(G1).UnmarshalPublicKey(inf1); err == nil {
+		t.Error("G1 identity accepted")
+	}
+	inf2 := new(G2).ScalarBaseMult(new(big.Int)).Marshal()
+	if _, err := new(G2).UnmarshalPublicKey(inf2); err == nil {
+		t.Error("G2 identity accepted")
+	}
+
+	outside := &G2{twistPointOutsideG2(t, &vectorSource{seed: "public key outside G2"})}
+	if _, err := new(G2).UnmarshalPublicKey(outside.Marshal()); err == nil {
+		t.Error("G2 point outside of the subgroup accepted")
+	}
+}
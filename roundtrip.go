@@ -0,0 +1,65 @@
+package bn256
+
+// The functions in this file check that an encoding decodes back to the value
+// it was produced from. The marshal functions call them when the package is
+// built with the bn256debug tag, so that an encoding bug panics where it is
+// introduced instead of corrupting data that is read back much later. They
+// decode with the trusted or most lenient decoder of each encoding, so they
+// test the encoding rather than the validity of the value. The fixed
+// encodings of the point at infinity are not checked.
+
+func checkRoundTripG1(e *G1, m []byte) {
+	got := new(G1)
+	if _, err := got.UnmarshalTrusted(m); err != nil || !got.Equal(e) {
+		panic("bn256: G1 encoding doesn't decode to the encoded point")
+	}
+}
+
+func checkRoundTripG1Compressed(e *G1, m []byte) {
+	got := new(G1)
+	if _, err := got.UnmarshalCompressed(m); err != nil || !got.Equal(e) {
+		panic("bn256: compressed G1 encoding doesn't decode to the encoded point")
+	}
+}
+
+func checkRoundTripG2(e *G2, m []byte) {
+	got := new(G2)
+	if _, err := got.UnmarshalTrusted(m); err != nil || !got.Equal(e) {
+		panic("bn256: G2 encoding doesn't decode to the encoded point")
+	}
+}
+
+func checkRoundTripG2Compressed(e *G2, m []byte) {
+	got := new(G2)
+	if _, err := got.unmarshalCompressedConstTime(m, false); err != nil || !got.Equal(e) {
+		panic("bn256: compressed G2 encoding doesn't decode to the encoded point")
+	}
+}
+
+func checkRoundTripG2Ordered(e *G2, m []byte, order G2CoordinateOrder) {
+	got := new(G2)
+	if _, err := got.UnmarshalOrdered(m, order); err != nil || !got.Equal(e) {
+		panic("bn256: ordered G2 encoding doesn't decode to the encoded point")
+	}
+}
+
+func checkRoundTripG2Untwisted(e *G2, m []byte) {
+	got := new(G2)
+	if _, err := got.UnmarshalUntwisted(m); err != nil || !got.Equal(e) {
+		panic("bn256: untwisted G2 encoding doesn't decode to the encoded point")
+	}
+}
+
+func checkRoundTripGT(e *GT, m []byte) {
+	got := new(GT)
+	if _, err := got.Unmarshal(m); err != nil || !got.Equal(e) {
+		panic("bn256: GT encoding doesn't decode to the encoded element")
+	}
+}
+
+func checkRoundTripGTCompressed(e *GT, m []byte) {
+	got := new(GT)
+	if _, err := got.unmarshalCompressed(m, false); err != nil || !got.Equal(e) {
+		panic("bn256: compressed GT encoding doesn't decode to the encoded element")
+	}
+}
@@ -0,0 +1,117 @@
+package bn256
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ScalarSize is the length of an encoded scalar.
+const ScalarSize = 32
+
+// ScalarWideSize is the length of the inputs of ScalarReduce that makes the
+// bias of the reduction negligible.
+const ScalarWideSize = 2 * ScalarSize
+
+// modOrder returns k mod Order. It returns k itself if it is already reduced.
+func modOrder(k *big.Int) *big.Int {
+	if k.Sign() < 0 || k.Cmp(Order) >= 0 {
+		return new(big.Int).Mod(k, Order)
+	}
+	return k
+}
+
+// ScalarSqrt returns a square root of a modulo Order and true, or nil and
+// false if a is not a square modulo Order. a is reduced modulo Order first.
+// Order ≡ 1 mod 4, so the root is found with the Tonelli-Shanks algorithm of
+// big.Int.ModSqrt. The other root is Order minus the returned one.
+//
+// ScalarSqrt is not constant time.
+func ScalarSqrt(a *big.Int) (*big.Int, bool) {
+	t := new(big.Int).Mod(a, Order)
+	if t.ModSqrt(t, Order) == nil {
+		return nil, false
+	}
+	return t, true
+}
+
+// ScalarInverse returns a⁻¹ mod Order, the scalar whose product with a is 1
+// modulo Order, as needed for example to compute (h+s)⁻¹ in SM9 signing. a is
+// reduced modulo Order first, and an error is returned if the result is zero,
+// which has no inverse.
+//
+// ScalarInverse is not constant time; use ScalarInverseCT for secret scalars.
+func ScalarInverse(a *big.Int) (*big.Int, error) {
+	t := new(big.Int).Mod(a, Order)
+	if t.Sign() == 0 {
+		return nil, errors.New("bn256: scalar is zero modulo Order")
+	}
+	return t.ModInverse(t, Order), nil
+}
+
+// ScalarNeg returns -k mod Order, which is in [0, Order). k may be negative or
+// not less than Order; in particular ScalarNeg returns zero for every multiple
+// of Order.
+func ScalarNeg(k *big.Int) *big.Int {
+	t := new(big.Int).Mod(k, Order)
+	if t.Sign() == 0 {
+		return t
+	}
+	return t.Sub(Order, t)
+}
+
+// ScalarToBytes returns k mod Order as a ScalarSize-byte big-endian number.
+// Unlike big.Int.Bytes, the result always has the same length, with leading
+// zeros kept.
+func ScalarToBytes(k *big.Int) []byte {
+	le := reduceScalar(k)
+	out := make([]byte, ScalarSize)
+	for i := range out {
+		out[i] = le[ScalarSize-1-i]
+	}
+	return out
+}
+
+// ScalarToBytesLE is like ScalarToBytes, but returns a little-endian number.
+func ScalarToBytesLE(k *big.Int) []byte {
+	le := reduceScalar(k)
+	return le[:]
+}
+
+// ScalarFromBytes decodes a ScalarSize-byte big-endian number, as returned by
+// ScalarToBytes. It returns an error if b has the wrong length or if the
+// number is not less than Order, so every scalar has a single encoding.
+func ScalarFromBytes(b []byte) (*big.Int, error) {
+	if len(b) != ScalarSize {
+		return nil, errors.New("bn256: scalar has the wrong length")
+	}
+	k := new(big.Int).SetBytes(b)
+	if k.Cmp(Order) >= 0 {
+		return nil, errors.New("bn256: scalar not reduced modulo Order")
+	}
+	return k, nil
+}
+
+// ScalarFromBytesLE is like ScalarFromBytes, but decodes a little-endian
+// number, as returned by ScalarToBytesLE.
+func ScalarFromBytesLE(b []byte) (*big.Int, error) {
+	if len(b) != ScalarSize {
+		return nil, errors.New("bn256: scalar has the wrong length")
+	}
+	be := make([]byte, ScalarSize)
+	for i := range be {
+		be[i] = b[ScalarSize-1-i]
+	}
+	return ScalarFromBytes(be)
+}
+
+// ScalarReduce interprets b as a big-endian number of any length and returns
+// it modulo Order, for deriving scalars from hash outputs, as in key
+// derivation or Fiat–Shamir challenges. Reducing a uniform n-byte string
+// makes some scalars more likely than others by about 2⁻⁽⁸ⁿ⁻²⁵⁶⁾, so b
+// should be at least ScalarWideSize bytes long, for a bias of about 2⁻²⁵⁶.
+// A ScalarSize-byte b is reduced correctly but the result is far from
+// uniform: the scalars below 2²⁵⁶ - Order are twice as likely as the others.
+func ScalarReduce(b []byte) *big.Int {
+	k := new(big.Int).SetBytes(b)
+	return k.Mod(k, Order)
+}
@@ -0,0 +1,228 @@
+package bn256
+
+import (
+	"bytes"
+	"testing"
+
+	"crypto/rand"
+	"math/big"
+)
+
+func TestScalarSqrt(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		k, _ := rand.Int(rand.Reader, Order)
+		a := new(big.Int).Mul(k, k)
+
+		root, ok := ScalarSqrt(a)
+		if !ok {
+			t.Fatal("square has no root")
+		}
+		root.Mul(root, root).Mod(root, Order)
+		if root.Cmp(a.Mod(a, Order)) != 0 {
+			t.Fatal("root² != a")
+		}
+	}
+
+	if root, ok := ScalarSqrt(new(big.Int)); !ok || root.Sign() != 0 {
+		t.Fatal("bad root of zero")
+	}
+	if root, ok := ScalarSqrt(new(big.Int).Sub(Order, big.NewInt(1))); !ok {
+		t.Fatal("-1 has no root, but Order ≡ 1 mod 4")
+	} else if root.Mul(root, root).Add(root, big.NewInt(1)).Mod(root, Order).Sign() != 0 {
+		t.Fatal("root² != -1")
+	}
+
+	// 7 is the smallest quadratic non-residue modulo Order.
+	for n := int64(2); n < 7; n++ {
+		if _, ok := ScalarSqrt(big.NewInt(n)); !ok {
+			t.Fatalf("%d has no root", n)
+		}
+	}
+	if _, ok := ScalarSqrt(big.NewInt(7)); ok {
+		t.Fatal("7 has a root")
+	}
+}
+
+func TestScalarInverse(t *testing.T) {
+	one := big.NewInt(1)
+	in := []*big.Int{
+		one,
+		big.NewInt(2),
+		big.NewInt(-1),
+		new(big.Int).Sub(Order, one),
+		new(big.Int).Add(Order, one),
+		new(big.Int).Lsh(one, 255),
+		new(big.Int).Lsh(one, 300),
+	}
+	for i := 0; i < 16; i++ {
+		k, _ := rand.Int(rand.Reader, Order)
+		in = append(in, k)
+	}
+
+	for _, a := range in {
+		inv, err := ScalarInverse(a)
+		if err != nil {
+			t.Fatalf("ScalarInverse(%v): %v", a, err)
+		}
+		invCT, err := ScalarInverseCT(a)
+		if err != nil {
+			t.Fatalf("ScalarInverseCT(%v): %v", a, err)
+		}
+		if inv.Sign() < 0 || inv.Cmp(Order) >= 0 || inv.Cmp(invCT) != 0 {
+			t.Fatalf("inverses of %v: %v and %v", a, inv, invCT)
+		}
+		if prod := new(big.Int).Mul(a, inv); prod.Mod(prod, Order).Cmp(one) != 0 {
+			t.Fatalf("a·ScalarInverse(a) ≠ 1 for a = %v", a)
+		}
+	}
+
+	for _, a := range []*big.Int{new(big.Int), Order, new(big.Int).Neg(Order), new(big.Int).Lsh(Order, 3)} {
+		if inv, err := ScalarInverse(a); err == nil {
+			t.Fatalf("ScalarInverse(%v) = %v, want an error", a, inv)
+		}
+		if inv, err := ScalarInverseCT(a); err == nil {
+			t.Fatalf("ScalarInverseCT(%v) = %v, want an error", a, inv)
+		}
+	}
+}
+
+func BenchmarkScalarInverse(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+	b.Run("ModInverse", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ScalarInverse(k)
+		}
+	})
+	b.Run("Fermat", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ScalarInverseCT(k)
+		}
+	})
+}
+
+func TestScalarNeg(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	for _, k := range []*big.Int{
+		k,
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Set(Order),
+		new(big.Int).Add(Order, k),
+		new(big.Int).Neg(k),
+	} {
+		neg := ScalarNeg(k)
+		if neg.Sign() < 0 || neg.Cmp(Order) >= 0 {
+			t.Fatalf("ScalarNeg(%v) = %v isn't reduced", k, neg)
+		}
+		sum := new(big.Int).Add(k, neg)
+		if sum.Mod(sum, Order).Sign() != 0 {
+			t.Fatalf("%v + ScalarNeg(%v) != 0 mod Order", k, k)
+		}
+	}
+}
+
+func TestScalarBytes(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	for _, k := range []*big.Int{
+		k,
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(0x0102),
+		new(big.Int).Sub(Order, big.NewInt(1)),
+	} {
+		be, le := ScalarToBytes(k), ScalarToBytesLE(k)
+		if len(be) != ScalarSize || len(le) != ScalarSize {
+			t.Fatalf("%v: encodings have lengths %d and %d", k, len(be), len(le))
+		}
+		for i := range be {
+			if be[i] != le[ScalarSize-1-i] {
+				t.Fatalf("%v: little-endian encoding isn't reversed", k)
+			}
+		}
+
+		got, err := ScalarFromBytes(be)
+		if err != nil || got.Cmp(k) != 0 {
+			t.Fatalf("ScalarFromBytes(%x) = %v, %v; want %v", be, got, err, k)
+		}
+		got, err = ScalarFromBytesLE(le)
+		if err != nil || got.Cmp(k) != 0 {
+			t.Fatalf("ScalarFromBytesLE(%x) = %v, %v; want %v", le, got, err, k)
+		}
+	}
+
+	// Small scalars are zero-padded.
+	want := make([]byte, ScalarSize)
+	want[ScalarSize-2], want[ScalarSize-1] = 0x01, 0x02
+	if got := ScalarToBytes(big.NewInt(0x0102)); !bytes.Equal(got, want) {
+		t.Fatalf("ScalarToBytes(0x0102) = %x", got)
+	}
+	want[0], want[1], want[ScalarSize-2], want[ScalarSize-1] = 0x02, 0x01, 0, 0
+	if got := ScalarToBytesLE(big.NewInt(0x0102)); !bytes.Equal(got, want) {
+		t.Fatalf("ScalarToBytesLE(0x0102) = %x", got)
+	}
+
+	// Scalars are reduced before encoding.
+	if got := ScalarToBytes(new(big.Int).Neg(big.NewInt(1))); !bytes.Equal(got, ScalarToBytes(new(big.Int).Sub(Order, big.NewInt(1)))) {
+		t.Fatal("ScalarToBytes(-1) != ScalarToBytes(Order-1)")
+	}
+
+	for _, b := range [][]byte{
+		Order.FillBytes(make([]byte, ScalarSize)),
+		make([]byte, ScalarSize-1),
+		make([]byte, ScalarSize+1),
+	} {
+		if _, err := ScalarFromBytes(b); err == nil {
+			t.Errorf("ScalarFromBytes(%x) succeeded", b)
+		}
+		if _, err := ScalarFromBytesLE(b); err == nil && len(b) != ScalarSize {
+			t.Errorf("ScalarFromBytesLE(%x) succeeded", b)
+		}
+	}
+	orderLE := ScalarToBytesLE(new(big.Int).Sub(Order, big.NewInt(1)))
+	orderLE[0]++
+	if _, err := ScalarFromBytesLE(orderLE); err == nil {
+		t.Error("ScalarFromBytesLE(Order) succeeded")
+	}
+}
+
+func TestScalarReduce(t *testing.T) {
+	one := big.NewInt(1)
+	for _, k := range []*big.Int{
+		new(big.Int),
+		new(big.Int).Sub(Order, one),
+		new(big.Int).Set(Order),
+		new(big.Int).Add(Order, one),
+		new(big.Int).Sub(p, one),
+		new(big.Int).Set(p),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 256), one),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 8*ScalarWideSize), one),
+		new(big.Int).Mul(Order, Order),
+	} {
+		want := new(big.Int).Mod(k, Order)
+		if got := ScalarReduce(k.Bytes()); got.Cmp(want) != 0 {
+			t.Errorf("ScalarReduce(%x) = %v, want %v", k, got, want)
+		}
+		// Leading zeros don't change the value.
+		padded := append(make([]byte, ScalarWideSize), k.Bytes()...)
+		if got := ScalarReduce(padded); got.Cmp(want) != 0 {
+			t.Errorf("ScalarReduce of %x with leading zeros = %v, want %v", k, got, want)
+		}
+	}
+
+	// p = Order + 6u², so the field size reduces to the eigenvalue of ψ.
+	if got := ScalarReduce(p.Bytes()); got.Cmp(sixuSquared) != 0 {
+		t.Errorf("ScalarReduce(p) = %v, want 6u²", got)
+	}
+	if got := ScalarReduce(nil); got.Sign() != 0 {
+		t.Errorf("ScalarReduce(nil) = %v", got)
+	}
+
+	for i := 0; i < 8; i++ {
+		b := make([]byte, ScalarWideSize)
+		rand.Read(b)
+		want := new(big.Int).Mod(new(big.Int).SetBytes(b), Order)
+		if got := ScalarReduce(b); got.Cmp(want) != 0 || got.Cmp(Order) >= 0 {
+			t.Fatalf("ScalarReduce(%x) = %v, want %v", b, got, want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package bn256
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// The constant-time scalar multiplications use a fixed window of ctWindow
+// bits. The scalar is first made odd, by adding Order to it if it is even,
+// and then recoded into ctDigits signed digits that are all odd and in
+// [-15, 15]. As no digit is zero, every window costs exactly ctWindow
+// doublings, one table lookup and one addition, whatever the scalar. The
+// constant-time exponentiation in GT works the same way, with squarings and
+// multiplications.
+//
+// For points of order Order the additions don't branch either: every partial
+// sum before the last window is an odd multiple of the point smaller than
+// Order, so it is never the point at infinity and never equal to plus or
+// minus the table entry that is added to it. Only the last addition may be,
+// which yields the point at infinity without a branch for scalars that are
+// multiples of Order and doubles for a negligible fraction of the others.
+const (
+	ctWindow = 4
+	ctDigits = scalarBits/ctWindow + 1
+)
+
+// recodeScalarCT returns the signed digits d of k mod Order, or of
+// k mod Order + Order if that is even, such that ∑ d[i]·16^i is that odd
+// value. Apart from the reduction of k, which only branches on whether k is
+// already reduced, it doesn't branch on k.
+func recodeScalarCT(k *big.Int) (digits [ctDigits]int8) {
+	s := reduceScalar(k)
+	var w [5]uint64
+	for i := 0; i < 4; i++ {
+		w[i] = binary.LittleEndian.Uint64(s[8*i:])
+	}
+
+	even := -(1 ^ w[0]&1)
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		w[i], carry = bits.Add64(w[i], orderWords[i]&even, carry)
+	}
+	w[4] = carry
+
+	// For an odd w, w = (w mod 32 - 16) + 16·((w >> 4) | 1) and (w >> 4) | 1
+	// is odd again.
+	for i := 0; i < ctDigits-1; i++ {
+		digits[i] = int8(w[0]&31) - 16
+		for j := 0; j < 4; j++ {
+			w[j] = w[j]>>ctWindow | w[j+1]<<(64-ctWindow)
+		}
+		w[4] >>= ctWindow
+		w[0] |= 1
+	}
+	digits[ctDigits-1] = int8(w[0])
+
+	clearBytes(s[:])
+	clearWords(w[:])
+	return digits
+}
+
+// ctDigit splits the odd digit d into the index (|d|-1)/2 of |d|·P in the
+// table of odd multiples of P and a flag that is 1 if d is negative.
+func ctDigit(d int8) (idx, neg uint64) {
+	v := int64(d)
+	neg = uint64(v) >> 63
+	abs := uint64((v ^ -int64(neg)) + int64(neg))
+	return abs >> 1, neg
+}
+
+// ctEqual returns 1 if a == b and 0 otherwise, without branching.
+func ctEqual(a, b uint64) uint64 {
+	d := a ^ b
+	return 1 ^ ((d | -d) >> 63)
+}
+
+// ctLookup sets c to d·P, where table holds the odd multiples P, 3P, …, 15P.
+// It reads every entry of the table and selects through gfpCMov, so neither
+// the memory access pattern nor the branches depend on d.
+func (c *curvePoint) ctLookup(table *[8]curvePoint, d int8) {
+	idx, neg := ctDigit(d)
+	for i := range table {
+		cond := ctEqual(uint64(i), idx)
+		gfpCMov(&c.x, &c.x, &table[i].x, cond)
+		gfpCMov(&c.y, &c.y, &table[i].y, cond)
+		gfpCMov(&c.z, &c.z, &table[i].z, cond)
+		gfpCMov(&c.t, &c.t, &table[i].t, cond)
+	}
+	c.y.CNeg(&c.y, int(neg))
+}
+
+// MulCT sets c to scalar·a with a fixed sequence of group operations. See
+// ctWindow.
+func (c *curvePoint) MulCT(a *curvePoint, scalar *big.Int) {
+	var table [8]curvePoint
+	table[0].Set(a)
+	double := &curvePoint{}
+	double.Double(a)
+	for i := 1; i < len(table); i++ {
+		table[i].Add(&table[i-1], double)
+	}
+
+	digits := recodeScalarCT(scalar)
+	defer clearDigits(&digits)
+	sum, t := &curvePoint{}, &curvePoint{}
+	sum.ctLookup(&table, digits[ctDigits-1])
+	for i := ctDigits - 2; i >= 0; i-- {
+		for j := 0; j < ctWindow; j++ {
+			sum.Double(sum)
+		}
+		t.ctLookup(&table, digits[i])
+		sum.Add(sum, t)
+	}
+
+	c.Set(sum)
+}
+
+// ctLookup sets c to d·P, where table holds the odd multiples P, 3P, …, 15P.
+// It reads every entry of the table and selects through gfp2CMov, so neither
+// the memory access pattern nor the branches depend on d.
+func (c *twistPoint) ctLookup(table *[8]twistPoint, d int8) {
+	idx, neg := ctDigit(d)
+	for i := range table {
+		cond := ctEqual(uint64(i), idx)
+		gfp2CMov(&c.x, &c.x, &table[i].x, cond)
+		gfp2CMov(&c.y, &c.y, &table[i].y, cond)
+		gfp2CMov(&c.z, &c.z, &table[i].z, cond)
+		gfp2CMov(&c.t, &c.t, &table[i].t, cond)
+	}
+	c.y.CNeg(&c.y, int(neg))
+}
+
+// MulCT sets c to scalar·a with a fixed sequence of group operations. See
+// ctWindow.
+func (c *twistPoint) MulCT(a *twistPoint, scalar *big.Int) {
+	var table [8]twistPoint
+	table[0].Set(a)
+	double := &twistPoint{}
+	double.Double(a)
+	for i := 1; i < len(table); i++ {
+		table[i].Add(&table[i-1], double)
+	}
+
+	digits := recodeScalarCT(scalar)
+	defer clearDigits(&digits)
+	sum, t := &twistPoint{}, &twistPoint{}
+	sum.ctLookup(&table, digits[ctDigits-1])
+	for i := ctDigits - 2; i >= 0; i-- {
+		for j := 0; j < ctWindow; j++ {
+			sum.Double(sum)
+		}
+		t.ctLookup(&table, digits[i])
+		sum.Add(sum, t)
+	}
+
+	c.Set(sum)
+}
+
+// ctLookup sets e to a^d, where table holds the odd powers a, a³, …, a¹⁵ of
+// an element a of GT. It reads every entry of the table and selects through
+// gfp12CMov, and inverts a power by conjugating it, which is correct in the
+// cyclotomic subgroup, so neither the memory access pattern nor the branches
+// depend on d.
+func (e *gfP12) ctLookup(table *[8]gfP12, d int8) {
+	idx, neg := ctDigit(d)
+	for i := range table {
+		gfp12CMov(e, e, &table[i], ctEqual(uint64(i), idx))
+	}
+	inv := (&gfP12{}).Conjugate(e)
+	gfp12CMov(e, e, inv, neg)
+}
+
+// expCT sets e to a^power with a fixed sequence of operations and then
+// returns e. a MUST be an element of GT: besides the cyclotomic squarings,
+// expCT relies on a^Order being one to make the exponent odd. See ctWindow.
+func (e *gfP12) expCT(a *gfP12, power *big.Int) *gfP12 {
+	var table [8]gfP12
+	table[0].Set(a)
+	square := (&gfP12{}).SquareCyclo6(a)
+	for i := 1; i < len(table); i++ {
+		table[i].Mul(&table[i-1], square)
+	}
+
+	digits := recodeScalarCT(power)
+	defer clearDigits(&digits)
+	sum, t := &gfP12{}, &gfP12{}
+	sum.ctLookup(&table, digits[ctDigits-1])
+	for i := ctDigits - 2; i >= 0; i-- {
+		for j := 0; j < ctWindow; j++ {
+			sum.SquareCyclo6(sum)
+		}
+		t.ctLookup(&table, digits[i])
+		sum.Mul(sum, t)
+	}
+
+	return e.Set(sum)
+}
+
+// scalarMontMul sets c to a·b·2⁻²⁵⁶ mod Order, for a and b less than Order,
+// with word-by-word Montgomery multiplication. The final subtraction is done
+// with a mask, so it doesn't branch on the values.
+func scalarMontMul(c, a, b *[4]uint64) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var carry, c0 uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			lo, c0 = bits.Add64(lo, t[j], 0)
+			hi += c0
+			lo, c0 = bits.Add64(lo, carry, 0)
+			hi += c0
+			t[j], carry = lo, hi
+		}
+		t[4], t[5] = bits.Add64(t[4], carry, 0)
+
+		m := t[0] * orderNp
+		hi, lo := bits.Mul64(m, orderWords[0])
+		_, c0 = bits.Add64(lo, t[0], 0)
+		carry = hi + c0
+		for j := 1; j < 4; j++ {
+			hi, lo := bits.Mul64(m, orderWords[j])
+			lo, c0 = bits.Add64(lo, t[j], 0)
+			hi += c0
+			lo, c0 = bits.Add64(lo, carry, 0)
+			hi += c0
+			t[j-1], carry = lo, hi
+		}
+		t[3], c0 = bits.Add64(t[4], carry, 0)
+		t[4] = t[5] + c0
+	}
+
+	// t is less than 2·Order: subtract Order unless that borrows.
+	var d [4]uint64
+	var borrow uint64
+	for j := range d {
+		d[j], borrow = bits.Sub64(t[j], orderWords[j], borrow)
+	}
+	_, borrow = bits.Sub64(t[4], 0, borrow)
+	mask := -borrow
+	for j := range c {
+		c[j] = d[j] ^ (mask & (d[j] ^ t[j]))
+	}
+	clearWords(t[:])
+	clearWords(d[:])
+}
+
+// ScalarInverseCT is like ScalarInverse, but computes a^(Order-2) mod Order,
+// which by Fermat's little theorem is a⁻¹, with fixed-size Montgomery
+// arithmetic. The sequence of operations only depends on Order and the
+// arithmetic doesn't branch on a, so it is suitable for secret scalars. Only
+// the reduction of a, which branches on whether a is already reduced, and the
+// choice between the result and the error for a multiple of Order depend on
+// a.
+func ScalarInverseCT(a *big.Int) (*big.Int, error) {
+	s := reduceScalar(a)
+	var x [4]uint64
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint64(s[8*i:])
+	}
+	clearBytes(s[:])
+	zero := ctEqual(x[0]|x[1]|x[2]|x[3], 0)
+
+	scalarMontMul(&x, &x, &orderR2)
+	acc := x
+	for i := scalarBits - 2; i >= 0; i-- {
+		scalarMontMul(&acc, &acc, &acc)
+		if orderMinus2[i/64]>>(uint(i)%64)&1 == 1 {
+			scalarMontMul(&acc, &acc, &x)
+		}
+	}
+	scalarMontMul(&acc, &acc, &[4]uint64{1})
+
+	var be [ScalarSize]byte
+	for i := range acc {
+		binary.BigEndian.PutUint64(be[ScalarSize-8*(i+1):], acc[i])
+	}
+	ret := new(big.Int).SetBytes(be[:])
+	clearWords(x[:])
+	clearWords(acc[:])
+	clearBytes(be[:])
+	if zero == 1 {
+		return nil, errors.New("bn256: scalar is zero modulo Order")
+	}
+	return ret, nil
+}
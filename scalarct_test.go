@@ -0,0 +1,202 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math"
+	"math/big"
+	mathrand "math/rand"
+	"testing"
+	"time"
+)
+
+// ctTestScalars returns the scalars the constant-time tests run on: the edge
+// cases around zero, Order and 2²⁵⁶, and a few random ones.
+func ctTestScalars() []*big.Int {
+	one := big.NewInt(1)
+	ks := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(-1),
+		new(big.Int).Sub(Order, one),
+		new(big.Int).Set(Order),
+		new(big.Int).Add(Order, one),
+		new(big.Int).Lsh(one, 255),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 256), one),
+	}
+	for i := 0; i < 4; i++ {
+		k, _ := rand.Int(rand.Reader, Order)
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+func TestRecodeScalarCT(t *testing.T) {
+	sixteen := big.NewInt(16)
+	for _, k := range ctTestScalars() {
+		digits := recodeScalarCT(k)
+
+		sum := new(big.Int)
+		for i := len(digits) - 1; i >= 0; i-- {
+			d := digits[i]
+			if d%2 == 0 || d < -15 || d > 15 {
+				t.Fatalf("k = %v: digit %d is %d", k, i, d)
+			}
+			sum.Mul(sum, sixteen).Add(sum, big.NewInt(int64(d)))
+		}
+		want := new(big.Int).Mod(k, Order)
+		if sum.Bit(0) == 0 || new(big.Int).Mod(sum, Order).Cmp(want) != 0 {
+			t.Fatalf("k = %v: digits sum to %v", k, sum)
+		}
+	}
+}
+
+// TestScalarMultCTSchedule replays the windows of MulCT on the integers. For
+// every scalar, each window must add a non-zero table entry to a partial sum
+// that is neither the point at infinity nor plus or minus that entry, so that
+// curvePoint.Add and twistPoint.Add take their generic path every time and
+// the sequence of group operations is the same for every scalar.
+func TestScalarMultCTSchedule(t *testing.T) {
+	sixteen := big.NewInt(16)
+	for _, k := range ctTestScalars() {
+		digits := recodeScalarCT(k)
+
+		sum := big.NewInt(int64(digits[len(digits)-1]))
+		for i := len(digits) - 2; i >= 0; i-- {
+			sum.Mul(sum, sixteen)
+			d := big.NewInt(int64(digits[i]))
+			for _, bad := range []*big.Int{new(big.Int), d, new(big.Int).Neg(d)} {
+				diff := new(big.Int).Sub(sum, bad)
+				if i > 0 && diff.Mod(diff, Order).Sign() == 0 {
+					t.Fatalf("k = %v: exceptional addition in window %d", k, i)
+				}
+			}
+			sum.Add(sum, d)
+		}
+	}
+}
+
+func TestScalarMultCT(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	for _, k := range ctTestScalars() {
+		// Compare with the double-and-add of k mod Order.
+		reduced := new(big.Int).Mod(k, Order)
+		want1 := new(G1).ScalarMult(g1, reduced)
+		got1 := new(G1).ScalarMultCT(g1, k)
+		if !bytes.Equal(got1.Marshal(), want1.Marshal()) {
+			t.Errorf("k = %v: G1 mismatch", k)
+		}
+
+		want2 := new(G2).ScalarMult(g2, reduced)
+		got2 := new(G2).ScalarMultCT(g2, k)
+		if !bytes.Equal(got2.Marshal(), want2.Marshal()) {
+			t.Errorf("k = %v: G2 mismatch", k)
+		}
+	}
+
+	// The result may alias the input.
+	k, _ := rand.Int(rand.Reader, Order)
+	want := new(G1).ScalarMult(g1, k)
+	if g1.ScalarMultCT(g1, k); !bytes.Equal(g1.Marshal(), want.Marshal()) {
+		t.Error("aliased G1 mismatch")
+	}
+}
+
+// TestScalarMultCTTiming compares the running time of G1.ScalarMultCT for the
+// scalar one, which has the shortest binary expansion, with that for a random
+// scalar.
+func TestScalarMultCTTiming(t *testing.T) {
+	skipUnlessTimingTests(t)
+
+	random, _ := rand.Int(rand.Reader, Order)
+	classes := [2]*big.Int{big.NewInt(1), random}
+	_, g, _ := RandomG1(rand.Reader)
+
+	const samples = 1000
+	var times [2][]float64
+	e := new(G1)
+	for i := 0; i < 2*samples; i++ {
+		c := mathrand.Intn(2)
+		start := time.Now()
+		e.ScalarMultCT(g, classes[c])
+		times[c] = append(times[c], float64(time.Since(start)))
+	}
+
+	tStat := welchT(times)
+	if math.Abs(tStat) > 10 {
+		t.Errorf("running time depends on the scalar: t = %.2f", tStat)
+	}
+}
+
+func BenchmarkScalarMultCT(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+	_, g, _ := RandomG1(rand.Reader)
+	e := new(G1)
+
+	b.Run("ScalarMult", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			e.ScalarMult(g, k)
+		}
+	})
+	b.Run("ScalarMultCT", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			e.ScalarMultCT(g, k)
+		}
+	})
+}
+
+func TestGTScalarMultCT(t *testing.T) {
+	_, g, _ := RandomGT(rand.Reader)
+	for _, k := range ctTestScalars() {
+		want := new(GT).ScalarMult(g, new(big.Int).Mod(k, Order))
+		if got := new(GT).ScalarMultCT(g, k); !got.Equal(want) {
+			t.Errorf("k = %v: GT mismatch", k)
+		}
+	}
+}
+
+// TestGTScalarMultCTTiming compares the running time of GT.ScalarMultCT for
+// the exponent one with that for a random exponent.
+func TestGTScalarMultCTTiming(t *testing.T) {
+	skipUnlessTimingTests(t)
+
+	random, _ := rand.Int(rand.Reader, Order)
+	classes := [2]*big.Int{big.NewInt(1), random}
+	_, g, _ := RandomGT(rand.Reader)
+
+	const samples = 500
+	var times [2][]float64
+	e := new(GT)
+	for i := 0; i < 2*samples; i++ {
+		c := mathrand.Intn(2)
+		start := time.Now()
+		e.ScalarMultCT(g, classes[c])
+		times[c] = append(times[c], float64(time.Since(start)))
+	}
+
+	tStat := welchT(times)
+	if math.Abs(tStat) > 10 {
+		t.Errorf("running time depends on the exponent: t = %.2f", tStat)
+	}
+}
+
+func BenchmarkGTScalarMultCT(b *testing.B) {
+	k, _ := rand.Int(rand.Reader, Order)
+	_, g, _ := RandomGT(rand.Reader)
+	e := new(GT)
+
+	b.Run("ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ScalarMult(g, k)
+		}
+	})
+	b.Run("ScalarMultCT", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ScalarMultCT(g, k)
+		}
+	})
+}
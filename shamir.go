@@ -0,0 +1,85 @@
+package bn256
+
+import (
+	"math/big"
+)
+
+// mulShamir sets c to a·p + b·q with Shamir's trick: the bits of both scalars
+// are scanned together from the most significant one, with one doubling per
+// bit and an addition of p, q or p+q from a three-entry table whenever either
+// bit is set. That is one set of doublings instead of two, and three
+// additions for every four bits instead of four. The scalars are reduced
+// modulo Order. Its running time depends on them.
+func (c *curvePoint) mulShamir(a *big.Int, p *curvePoint, b *big.Int, q *curvePoint) {
+	a, b = modOrder(a), modOrder(b)
+
+	// table[i] is p for i = 1, q for i = 2 and p+q for i = 3. Add takes care
+	// of the cases where q is ±p or either point is at infinity.
+	var table [4]curvePoint
+	table[1].Set(p)
+	table[2].Set(q)
+	table[3].Add(p, q)
+
+	n := a.BitLen()
+	if b.BitLen() > n {
+		n = b.BitLen()
+	}
+	sum := &curvePoint{}
+	sum.SetInfinity()
+	for i := n - 1; i >= 0; i-- {
+		sum.Double(sum)
+		if idx := a.Bit(i) | b.Bit(i)<<1; idx != 0 {
+			sum.Add(sum, &table[idx])
+		}
+	}
+
+	c.Set(sum)
+}
+
+// mulShamir sets c to a·p + b·q. See the same function for curvePoint.
+func (c *twistPoint) mulShamir(a *big.Int, p *twistPoint, b *big.Int, q *twistPoint) {
+	a, b = modOrder(a), modOrder(b)
+
+	var table [4]twistPoint
+	table[1].Set(p)
+	table[2].Set(q)
+	table[3].Add(p, q)
+
+	n := a.BitLen()
+	if b.BitLen() > n {
+		n = b.BitLen()
+	}
+	sum := &twistPoint{}
+	sum.SetInfinity()
+	for i := n - 1; i >= 0; i-- {
+		sum.Double(sum)
+		if idx := a.Bit(i) | b.Bit(i)<<1; idx != 0 {
+			sum.Add(sum, &table[idx])
+		}
+	}
+
+	c.Set(sum)
+}
+
+// CombinedMult sets e to a·p + b·q and then returns e. It computes the two
+// multiples together with Shamir's trick, which is faster than two
+// ScalarMult calls and an Add; see BenchmarkCombinedMult. Like ScalarMult, it
+// reduces a and b modulo Order. Its running time depends on the scalars, so
+// only use it with public ones, for example to verify a signature.
+func (e *G1) CombinedMult(a *big.Int, p *G1, b *big.Int, q *G1) *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.mulShamir(a, p.p, b, q.p)
+	return e
+}
+
+// CombinedMult sets e to a·p + b·q and then returns e, like G1.CombinedMult.
+// p and q must be in G₂.
+func (e *G2) CombinedMult(a *big.Int, p *G2, b *big.Int, q *G2) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.mulShamir(a, p.p, b, q.p)
+	return e
+}
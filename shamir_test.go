@@ -0,0 +1,91 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestCombinedMult(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, q1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+	_, q2, _ := RandomG2(rand.Reader)
+	inf1 := new(G1).ScalarBaseMult(new(big.Int))
+	inf2 := new(G2).ScalarBaseMult(new(big.Int))
+
+	k, _ := rand.Int(rand.Reader, Order)
+	scalars := []*big.Int{
+		new(big.Int),
+		big.NewInt(1),
+		big.NewInt(-3),
+		k,
+		new(big.Int).Add(k, Order),
+		new(big.Int).Sub(Order, big.NewInt(1)),
+	}
+
+	// The second point is either independent, equal to the first, its
+	// negation or at infinity.
+	pairs1 := [][2]*G1{{p1, q1}, {p1, p1}, {p1, new(G1).Neg(p1)}, {p1, inf1}, {inf1, q1}}
+	pairs2 := [][2]*G2{{p2, q2}, {p2, p2}, {p2, new(G2).Neg(p2)}, {p2, inf2}, {inf2, q2}}
+	for _, a := range scalars {
+		for _, b := range scalars {
+			for i, pq := range pairs1 {
+				want := new(G1).Add(new(G1).ScalarMult(pq[0], a), new(G1).ScalarMult(pq[1], b))
+				if got := new(G1).CombinedMult(a, pq[0], b, pq[1]); !got.Equal(want) {
+					t.Fatalf("G1, pair %d: %v·P + %v·Q mismatch", i, a, b)
+				}
+			}
+			for i, pq := range pairs2 {
+				want := new(G2).Add(new(G2).ScalarMult(pq[0], a), new(G2).ScalarMult(pq[1], b))
+				if got := new(G2).CombinedMult(a, pq[0], b, pq[1]); !got.Equal(want) {
+					t.Fatalf("G2, pair %d: %v·P + %v·Q mismatch", i, a, b)
+				}
+			}
+		}
+	}
+
+	// The result may alias the inputs.
+	want := new(G1).Add(new(G1).ScalarMult(p1, k), new(G1).ScalarMult(q1, k))
+	if p1.CombinedMult(k, p1, k, q1); !p1.Equal(want) {
+		t.Fatal("aliased CombinedMult mismatch")
+	}
+}
+
+// BenchmarkCombinedMult compares CombinedMult with two ScalarMult calls and an
+// Add.
+func BenchmarkCombinedMult(b *testing.B) {
+	k1, _ := rand.Int(rand.Reader, Order)
+	k2, _ := rand.Int(rand.Reader, Order)
+	_, p1, _ := RandomG1(rand.Reader)
+	_, q1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+	_, q2, _ := RandomG2(rand.Reader)
+	e1, t1 := new(G1), new(G1)
+	e2, t2 := new(G2), new(G2)
+
+	b.Run("G1/ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e1.ScalarMult(p1, k1)
+			t1.ScalarMult(q1, k2)
+			e1.Add(e1, t1)
+		}
+	})
+	b.Run("G1/CombinedMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e1.CombinedMult(k1, p1, k2, q1)
+		}
+	})
+	b.Run("G2/ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e2.ScalarMult(p2, k1)
+			t2.ScalarMult(q2, k2)
+			e2.Add(e2, t2)
+		}
+	})
+	b.Run("G2/CombinedMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e2.CombinedMult(k1, p2, k2, q2)
+		}
+	})
+}
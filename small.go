@@ -0,0 +1,17 @@
+// +build bn256small
+
+package bn256
+
+// smallMemory disables the large precomputed tables, trading speed for a
+// smaller footprint on memory-constrained targets such as WebAssembly. It is
+// set by building with the bn256small tag.
+//
+// With the tag, GT.ScalarBaseMult uses a plain square-and-multiply instead of
+// the 360 KiB table of powers of the generator, which makes it about five
+// times slower, and G1.ScalarBaseMult and G2.ScalarBaseMult use ScalarMultCT
+// instead of the 65 KiB and 130 KiB tables of multiples of the generators.
+// PairWithGenerator calls Pair instead of keeping the 16 KiB of line
+// functions of the G₂ generator. Tables that callers create explicitly, such
+// as PrecomputedG2 and G1MSMContext, are not affected. The field and tower
+// arithmetic doesn't recurse, so the tag doesn't change the stack depth.
+const smallMemory = true
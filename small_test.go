@@ -0,0 +1,38 @@
+// +build bn256small
+
+package bn256
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestSmallMemoryNoGTTable(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	PrecomputeGTGenerator()
+	new(GT).ScalarBaseMult(k)
+	if gtGenTable != nil {
+		t.Fatal("GT generator table was built")
+	}
+}
+
+func TestSmallMemoryNoGeneratorTables(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	PrecomputeG1Generator()
+	PrecomputeG2Generator()
+	new(G1).ScalarBaseMult(k)
+	new(G2).ScalarBaseMult(k)
+	if g1GenTable != nil || g2GenTable != nil {
+		t.Fatal("G1 or G2 generator table was built")
+	}
+}
+
+func TestSmallMemoryNoGeneratorLines(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	if !PairWithGenerator(g1).Equal(Pair(g1, &G2{twistGen})) {
+		t.Fatal("PairWithGenerator doesn't match Pair")
+	}
+	if g2GenLines != nil {
+		t.Fatal("G2 generator lines were built")
+	}
+}
@@ -0,0 +1,86 @@
+package bn256
+
+import (
+	"io"
+)
+
+var (
+	_ io.WriterTo = (*G1)(nil)
+	_ io.WriterTo = (*G2)(nil)
+	_ io.WriterTo = (*GT)(nil)
+)
+
+// The stream functions use the compressed encodings, which have a fixed size
+// for every value, including the identity, so that the readers know how much
+// to read without a length prefix. ReadG1, ReadG2 and ReadGT read a single
+// value and stop, so that values can be read one after the other from a
+// framed message. They are functions rather than ReadFrom methods because
+// io.ReaderFrom reads until EOF and treats it as success, which io.Copy
+// relies on.
+
+// writeTo writes m to w and returns the number of bytes written.
+func writeTo(w io.Writer, m []byte) (int64, error) {
+	n, err := w.Write(m)
+	return int64(n), err
+}
+
+// readValue reads exactly size bytes from r and decodes them with unmarshal.
+// It returns io.EOF if r had no data at all and io.ErrUnexpectedEOF if it
+// ended in the middle of the value.
+func readValue(r io.Reader, size int, unmarshal func([]byte) ([]byte, error)) error {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	_, err := unmarshal(buf)
+	return err
+}
+
+// WriteTo implements io.WriterTo. It writes the output of MarshalCompressed
+// to w and returns the number of bytes written.
+func (e *G1) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, e.MarshalCompressed())
+}
+
+// ReadG1 reads one point written by G1.WriteTo from r and decodes it with
+// UnmarshalCompressed. It doesn't read past the point. If r ends early it
+// returns io.EOF, when r had no data at all, or io.ErrUnexpectedEOF.
+func ReadG1(r io.Reader) (*G1, error) {
+	e := new(G1)
+	if err := readValue(r, g1CompressedSize, e.UnmarshalCompressed); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// WriteTo implements io.WriterTo. It writes the output of MarshalCompressed
+// to w and returns the number of bytes written.
+func (e *G2) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, e.MarshalCompressed())
+}
+
+// ReadG2 reads one point written by G2.WriteTo from r, like ReadG1.
+// UnmarshalCompressed checks that the point is in G₂.
+func ReadG2(r io.Reader) (*G2, error) {
+	e := new(G2)
+	if err := readValue(r, g2CompressedSize, e.UnmarshalCompressed); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// WriteTo implements io.WriterTo. It writes the output of MarshalCompressed
+// to w and returns the number of bytes written.
+func (e *GT) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, e.MarshalCompressed())
+}
+
+// ReadGT reads one element written by GT.WriteTo from r, like ReadG1.
+// UnmarshalCompressed checks that the element is in GT.
+func ReadGT(r io.Reader) (*GT, error) {
+	e := new(GT)
+	if err := readValue(r, gtCompressedSize, e.UnmarshalCompressed); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,121 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriteToRead(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+	_, c, _ := RandomGT(rand.Reader)
+	inf1 := new(G1).ScalarBaseMult(new(big.Int))
+	inf2 := new(G2).ScalarBaseMult(new(big.Int))
+	one := new(GT).ScalarBaseMult(new(big.Int))
+
+	buf := new(bytes.Buffer)
+	var written int64
+	for _, v := range []io.WriterTo{a, inf1, b, inf2, c, one} {
+		n, err := v.WriteTo(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		written += n
+	}
+	want := int64(2*g1CompressedSize + 2*g2CompressedSize + 2*gtCompressedSize)
+	if written != want || int64(buf.Len()) != want {
+		t.Fatalf("wrote %d bytes, buffer holds %d, want %d", written, buf.Len(), want)
+	}
+	data := buf.Bytes()
+
+	readers := map[string]func() io.Reader{
+		"Buffer":  func() io.Reader { return bytes.NewBuffer(data) },
+		"OneByte": func() io.Reader { return iotest.OneByteReader(bytes.NewReader(data)) },
+		"Half":    func() io.Reader { return iotest.HalfReader(bytes.NewReader(data)) },
+	}
+	for name, newReader := range readers {
+		r := newReader()
+		a2, err1 := ReadG1(r)
+		inf12, err2 := ReadG1(r)
+		b2, err3 := ReadG2(r)
+		inf22, err4 := ReadG2(r)
+		c2, err5 := ReadGT(r)
+		one2, err6 := ReadGT(r)
+		for _, err := range []error{err1, err2, err3, err4, err5, err6} {
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+		if !a2.Equal(a) || !inf12.Equal(inf1) || !b2.Equal(b) || !inf22.Equal(inf2) || !c2.Equal(c) || !one2.Equal(one) {
+			t.Fatalf("%s: values don't round-trip", name)
+		}
+		if _, err := ReadG1(r); err != io.EOF {
+			t.Fatalf("%s: reading past the end returned %v, want io.EOF", name, err)
+		}
+	}
+}
+
+func TestReadShort(t *testing.T) {
+	_, a, _ := RandomG1(rand.Reader)
+	_, b, _ := RandomG2(rand.Reader)
+	_, c, _ := RandomGT(rand.Reader)
+
+	for _, tc := range []struct {
+		m    []byte
+		read func(io.Reader) error
+	}{
+		{a.MarshalCompressed(), func(r io.Reader) error { _, err := ReadG1(r); return err }},
+		{b.MarshalCompressed(), func(r io.Reader) error { _, err := ReadG2(r); return err }},
+		{c.MarshalCompressed(), func(r io.Reader) error { _, err := ReadGT(r); return err }},
+	} {
+		short := tc.m[:len(tc.m)-1]
+		if err := tc.read(iotest.OneByteReader(bytes.NewReader(short))); err != io.ErrUnexpectedEOF {
+			t.Fatalf("reading %d of %d bytes returned %v", len(short), len(tc.m), err)
+		}
+	}
+
+	// Read errors other than EOF are passed through.
+	errRead := errors.New("read failed")
+	data := a.MarshalCompressed()
+	r := io.MultiReader(bytes.NewReader(data[:10]), errReader{errRead})
+	if g, err := ReadG1(r); err != errRead || g != nil {
+		t.Fatalf("ReadG1 returned %v, %v, want nil, %v", g, err, errRead)
+	}
+
+	// So are decoding errors: the flag byte 0x05 is invalid.
+	bad := append([]byte{0x05}, data[1:]...)
+	if _, err := ReadG1(bytes.NewReader(bad)); err == nil {
+		t.Fatal("ReadG1 accepted an invalid flag")
+	}
+}
+
+// errReader fails every read with err.
+type errReader struct{ err error }
+
+func (r errReader) Read(p []byte) (int, error) { return 0, r.err }
+
+// failingWriter accepts n bytes and then fails.
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errors.New("write failed")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestWriteToError(t *testing.T) {
+	_, b, _ := RandomG2(rand.Reader)
+	n, err := b.WriteTo(&failingWriter{n: 7})
+	if err == nil || n != 7 {
+		t.Fatalf("WriteTo returned %d, %v, want 7 and an error", n, err)
+	}
+}
@@ -0,0 +1,297 @@
+package bn256
+
+import (
+	"errors"
+)
+
+// psi sets c to ψ(a), where ψ is the endomorphism of the twist that maps it to
+// the full curve over GF(p¹²), applies the p-power Frobenius map there, and
+// maps the result back to the twist. See miller for the derivation. ψ works
+// directly on Jacobian coordinates, as the Frobenius map is a field
+// automorphism.
+func (c *twistPoint) psi(a *twistPoint) {
+	c.x.Conjugate(&a.x).Mul(&c.x, xiToPMinus1Over3)
+	c.y.Conjugate(&a.y).Mul(&c.y, xiToPMinus1Over2)
+	c.z.Conjugate(&a.z)
+	c.t.Square(&c.z)
+}
+
+// isInSubGroup reports whether c, which must be on the twist, is in G₂. On G₂,
+// ψ acts as multiplication by p ≡ 6u² mod Order, and for BN curves the converse
+// also holds: a point of the twist with ψ(c) = [6u²]c is in G₂. This takes a
+// 128-bit scalar multiplication rather than the 256-bit one of checking that
+// [Order]c is infinity.
+func (c *twistPoint) isInSubGroup() bool {
+	psi, mul := &twistPoint{}, &twistPoint{}
+	psi.psi(c)
+	mul.Mul(c, sixuSquared)
+	return psi.Equal(mul)
+}
+
+// isInSubGroupCT returns 1 if c, which must be an affine point on the twist,
+// is in G₂ and 0 otherwise. It makes the same check as isInSubGroup, but with
+// MulCT, whose sequence of operations only depends on the public scalar, and
+// compares the results without branching on them.
+//
+// MulCT multiplies by 6u² + Order, as 6u² is even, and that is p. The check
+// is still sound: on a component of c of order dividing the cofactor
+// 2p - Order, ψ = [p] would have to hold as well as ψ² - tψ + p = 0, which
+// forces that order to divide Order·p, so the component is zero.
+func (c *twistPoint) isInSubGroupCT() uint64 {
+	psi, mul := &twistPoint{}, &twistPoint{}
+	psi.psi(c)
+	mul.MulCT(c, sixuSquared)
+
+	// ψ(c) is affine, so compare it to mul as x·z² and y·z³.
+	zero := &gfP2{}
+	z2 := (&gfP2{}).Square(&mul.z)
+	x := (&gfP2{}).Mul(&psi.x, z2)
+	z2.Mul(z2, &mul.z)
+	y := (&gfP2{}).Mul(&psi.y, z2)
+	return gfp2Equal(x, &mul.x) & gfp2Equal(y, &mul.y) & (1 ^ gfp2Equal(&mul.z, zero))
+}
+
+// clearCofactor sets c to h(ψ)(a), where
+//
+//	h(ψ) = u + 3u·ψ + u·ψ² + ψ³
+//
+// is the polynomial of "Faster hashing to G2", Fuentes-Castañeda, Knapp and
+// Rodríguez-Henríquez, https://eprint.iacr.org/2008/530. It maps any point of
+// the twist into G₂ and costs two multiplications by the 63-bit u instead of
+// one by the 256-bit cofactor 2p-Order. On G₂ itself it acts as
+// multiplication by u + 3u·p + u·p² + p³ mod Order, which is non-zero, so c is
+// only infinity if a has no component in G₂.
+func (c *twistPoint) clearCofactor(a *twistPoint) {
+	t0, t1, t2 := &twistPoint{}, &twistPoint{}, &twistPoint{}
+
+	// t0 = [u]a, t1 = ψ([3u]a), t2 = ψ²([u]a). mulWNAF reduces its scalar
+	// modulo Order, which is harmless for u < Order even though a need not
+	// be in G₂.
+	t0.mulWNAF(a, u)
+	t1.Double(t0)
+	t1.Add(t1, t0)
+	t1.psi(t1)
+	t2.psi(t0)
+	t2.psi(t2)
+
+	sum := &twistPoint{}
+	sum.Add(t0, t1)
+	sum.Add(sum, t2)
+
+	// ψ³(a).
+	t0.psi(a)
+	t0.psi(t0)
+	t0.psi(t0)
+	c.Add(sum, t0)
+}
+
+// IsInSubGroup reports whether e is a point of G₁. The order of the curve is
+// Order, so every point on the curve is in G₁.
+func (e *G1) IsInSubGroup() bool {
+	return e.p.IsOnCurve()
+}
+
+// IsInSubGroup reports whether e is a point of G₂, the subgroup of order Order
+// of the twist. Unlike G₁, the twist has many points outside of G₂.
+func (e *G2) IsInSubGroup() bool {
+	return e.p.IsOnCurve() && e.p.isInSubGroup()
+}
+
+// isInSubGroup reports whether e is in GT, the subgroup of order Order of the
+// multiplicative group of GF(p¹²). GT lies in the cyclotomic subgroup, of
+// order Φ₁₂(p) = p⁴-p²+1, so e must be in it first. There the p-power
+// Frobenius map is exponentiation by p = 6u² + Order, and e^p = e^(6u²) means
+// e^Order = 1. The exponentiation by 6u² is done as two by u and one by 6
+// with cyclotomic squarings, which is much cheaper than one by Order.
+func (e *gfP12) isInSubGroup() bool {
+	if e.IsZero() {
+		return false
+	}
+
+	// e^(p⁴-p²+1) = 1, that is e^(p⁴)·e = e^(p²).
+	a, b := &gfP12{}, &gfP12{}
+	a.FrobeniusP4(e).Mul(a, e)
+	b.FrobeniusP2(e)
+	if *a != *b {
+		return false
+	}
+
+	a.Frobenius(e)
+	b.PowToUCyclo6(e)
+	b.PowToUCyclo6(b)
+	b.expU64Cyclo6(b, 6)
+	return *a == *b
+}
+
+// IsInSubGroup reports whether e is an element of GT, the subgroup of order
+// Order of GF(p¹²). Most non-zero elements of GF(p¹²) are not.
+func (e *GT) IsInSubGroup() bool {
+	return e.p.isInSubGroup()
+}
+
+// IsOnCurve reports whether e is a valid point of the curve: its coordinates
+// are reduced modulo p and satisfy the curve equation. e may be in the
+// projective form that arithmetic leaves it in, and isn't modified, so
+// IsOnCurve is safe to call concurrently with other reads of e. The point at
+// infinity is on the curve; the zero value of G1 is not. As every point on the
+// curve is in G₁, this is the same check as IsInSubGroup.
+func (e *G1) IsOnCurve() bool {
+	if e.p == nil {
+		return false
+	}
+	c := &curvePoint{}
+	c.Set(e.p)
+	if lessThanPCT(&c.x)&lessThanPCT(&c.y)&lessThanPCT(&c.z) != 1 {
+		return false
+	}
+	return c.IsOnCurve()
+}
+
+// IsOnCurve reports whether e is a valid point of the twist, like
+// G1.IsOnCurve. Points of the twist need not be in G₂; use IsInSubGroup to
+// check that too.
+func (e *G2) IsOnCurve() bool {
+	if e.p == nil {
+		return false
+	}
+	c := &twistPoint{}
+	c.Set(e.p)
+	for _, v := range []*gfP{&c.x.x, &c.x.y, &c.y.x, &c.y.y, &c.z.x, &c.z.y} {
+		if lessThanPCT(v) != 1 {
+			return false
+		}
+	}
+	return c.IsOnCurve()
+}
+
+// IsValid reports whether e is an element of GT: its coordinates are reduced
+// modulo p and it has order dividing Order, as the result of a final
+// exponentiation does. It returns false for the zero value of GT and for
+// values of Miller and MillerLoop that haven't been through
+// FinalExponentiation, except by chance.
+func (e *GT) IsValid() bool {
+	if e.p == nil {
+		return false
+	}
+	for _, c := range []*gfP6{&e.p.x, &e.p.y} {
+		for _, v := range gfP6Coordinates(c) {
+			if lessThanPCT(v) != 1 {
+				return false
+			}
+		}
+	}
+	return e.p.isInSubGroup()
+}
+
+// UnmarshalStrict is like Unmarshal, but also checks that the point is in G₁.
+// Every point on the curve is, so it accepts the same inputs as Unmarshal. It
+// exists so that code handling untrusted data can use the strict form for all
+// three groups.
+func (e *G1) UnmarshalStrict(m []byte) ([]byte, error) {
+	return e.Unmarshal(m)
+}
+
+// UnmarshalStrict is like Unmarshal, but also returns an error if the point is
+// not in G₂. Points of the twist outside of G₂ pass Unmarshal, and feeding
+// them into a pairing or a scalar multiplication with a secret scalar allows
+// small-subgroup attacks, so use UnmarshalStrict on untrusted data. Like
+// Unmarshal, it leaves e unchanged on error.
+func (e *G2) UnmarshalStrict(m []byte) ([]byte, error) {
+	c := &G2{}
+	rest, err := c.Unmarshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if !c.p.isInSubGroup() {
+		return nil, errors.New("bn256: point not in G2")
+	}
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Set(c.p)
+	return rest, nil
+}
+
+// UnmarshalStrict is like Unmarshal, but also returns an error if the element
+// is not in GT. Any element of GF(p¹²) with reduced coordinates passes
+// Unmarshal, including elements of the cyclotomic subgroup that look like
+// pairing outputs but don't have order Order, and raising those to a secret
+// exponent allows small-subgroup attacks, so use UnmarshalStrict on untrusted
+// data. Like Unmarshal, it leaves e unchanged on error.
+func (e *GT) UnmarshalStrict(m []byte) ([]byte, error) {
+	c := &GT{}
+	rest, err := c.Unmarshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if !c.p.isInSubGroup() {
+		return nil, errors.New("bn256: element not in GT")
+	}
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Set(c.p)
+	return rest, nil
+}
+
+// ClearCofactor sets e to a point of G₂ derived from a, which must be on the
+// twist but may be outside of G₂, and then returns e. It applies the
+// polynomial h(ψ) = u + 3u·ψ + u·ψ² + ψ³ in the endomorphism ψ, which is much
+// faster than multiplying by the cofactor 2p-Order itself. The result is not
+// [2p-Order]a but a fixed non-zero multiple of it, and on G₂ ClearCofactor is
+// a multiplication by a fixed non-zero scalar.
+func (e *G2) ClearCofactor(a *G2) *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.clearCofactor(a.p)
+	return e
+}
+
+// BatchIsInSubGroupG1 reports whether each of points is in G₁, as
+// G1.IsInSubGroup would. Like G1.IsInSubGroup, it converts the points to
+// affine coordinates, but with a single batched inversion.
+func BatchIsInSubGroupG1(points []*G1) []bool {
+	cs := make([]*curvePoint, len(points))
+	for i, p := range points {
+		cs[i] = p.p
+	}
+	batchMakeAffine(cs)
+
+	ret := make([]bool, len(points))
+	for i, p := range points {
+		ret[i] = p.IsInSubGroup()
+	}
+	return ret
+}
+
+// BatchIsInSubGroupG2 reports whether each of points is in G₂, as
+// G2.IsInSubGroup would. The points are normalized with a single batched
+// inversion, so that the curve checks and ψ are computed on affine points.
+// The points themselves are not modified.
+func BatchIsInSubGroupG2(points []*G2) []bool {
+	zs := make([]gfP2, len(points))
+	for i, p := range points {
+		zs[i].Set(&p.p.z)
+	}
+	gfP2BatchInvert(zs, zs)
+
+	ret := make([]bool, len(points))
+	affine := &twistPoint{}
+	zInv2 := &gfP2{}
+	for i, p := range points {
+		if p.p.IsInfinity() {
+			ret[i] = true
+			continue
+		}
+
+		zInv2.Square(&zs[i])
+		affine.x.Mul(&p.p.x, zInv2)
+		affine.y.Mul(&p.p.y, zInv2).Mul(&affine.y, &zs[i])
+		affine.z.SetOne()
+		affine.t.SetOne()
+
+		ret[i] = affine.IsOnCurve() && affine.isInSubGroup()
+	}
+	return ret
+}
@@ -0,0 +1,394 @@
+package bn256
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"math/big"
+)
+
+// twistPointOutsideG2 returns a point of the twist that is not in G₂, found by
+// trying x-coordinates taken from src.
+func twistPointOutsideG2(t testing.TB, src *vectorSource) *twistPoint {
+	half := newGFp(2)
+	half.Invert(half)
+
+	// sqrt returns a square root of a, or nil.
+	sqrt := func(a *gfP) *gfP {
+		r, ok := (&gfP{}).Sqrt(a)
+		if !ok {
+			return nil
+		}
+		return r
+	}
+
+	for i := 0; i < 100; i++ {
+		x := src.nextGFp2()
+		y2 := (&gfP2{}).Square(x)
+		y2.Mul(y2, x).Add(y2, twistB)
+
+		// For y2 = a·i+b, a square root y = c·i+d satisfies d² = (b ± N)/2
+		// and c = a/2d, where N² = a²+b² is the norm of y2.
+		n, t1 := &gfP{}, &gfP{}
+		gfpMul(n, &y2.x, &y2.x)
+		gfpMul(t1, &y2.y, &y2.y)
+		gfpAdd(n, n, t1)
+		norm := sqrt(n)
+		if norm == nil {
+			continue
+		}
+
+		negNorm := &gfP{}
+		gfpNeg(negNorm, norm)
+		for _, s := range []*gfP{norm, negNorm} {
+			h := &gfP{}
+			gfpAdd(h, &y2.y, s)
+			gfpMul(h, h, half)
+			d := sqrt(h)
+			if d == nil || *d == (gfP{}) {
+				continue
+			}
+
+			y := &gfP2{}
+			y.y.Set(d)
+			inv := &gfP{}
+			gfpAdd(inv, d, d)
+			inv.Invert(inv)
+			gfpMul(&y.x, &y2.x, inv)
+
+			pt := &twistPoint{}
+			pt.x.Set(x)
+			pt.y.Set(y)
+			pt.z.SetOne()
+			pt.t.SetOne()
+			if !pt.IsOnCurve() {
+				t.Fatal("constructed point isn't on the twist")
+			}
+
+			check := &twistPoint{}
+			check.Mul(pt, Order)
+			if !check.IsInfinity() {
+				return pt
+			}
+		}
+	}
+
+	t.Fatal("no point outside of G₂ found")
+	return nil
+}
+
+func TestIsInSubGroup(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	if !g1.IsInSubGroup() {
+		t.Fatal("random G₁ point isn't in G₁")
+	}
+
+	_, g2, _ := RandomG2(rand.Reader)
+	if !g2.IsInSubGroup() {
+		t.Fatal("random G₂ point isn't in G₂")
+	}
+	if !new(G2).ScalarBaseMult(Order).IsInSubGroup() {
+		t.Fatal("point at infinity isn't in G₂")
+	}
+
+	bad := &G2{twistPointOutsideG2(t, &vectorSource{seed: "outside G2"})}
+	if bad.IsInSubGroup() {
+		t.Fatal("point outside of G₂ is reported to be in G₂")
+	}
+}
+
+func TestBatchIsInSubGroup(t *testing.T) {
+	src := &vectorSource{seed: "batch outside G2"}
+
+	g1s := make([]*G1, 4)
+	for i := range g1s {
+		_, g1s[i], _ = RandomG1(rand.Reader)
+	}
+	g1s = append(g1s, new(G1).ScalarBaseMult(Order))
+	for i, ok := range BatchIsInSubGroupG1(g1s) {
+		if ok != g1s[i].IsInSubGroup() {
+			t.Fatalf("G₁ point %d: BatchIsInSubGroupG1 doesn't match IsInSubGroup", i)
+		}
+	}
+
+	var g2s []*G2
+	for i := 0; i < 3; i++ {
+		_, g2, _ := RandomG2(rand.Reader)
+		// Keep the points in Jacobian form.
+		g2.Add(g2, g2)
+		g2s = append(g2s, g2, &G2{twistPointOutsideG2(t, src)})
+	}
+	g2s = append(g2s, new(G2).ScalarBaseMult(Order))
+
+	zs := make([]gfP2, len(g2s))
+	for i, p := range g2s {
+		zs[i] = p.p.z
+	}
+
+	got := BatchIsInSubGroupG2(g2s)
+	for i, p := range g2s {
+		if p.p.z != zs[i] {
+			t.Fatalf("G₂ point %d was modified", i)
+		}
+	}
+	for i, ok := range got {
+		if ok != g2s[i].IsInSubGroup() {
+			t.Fatalf("G₂ point %d: BatchIsInSubGroupG2 doesn't match IsInSubGroup", i)
+		}
+		if ok != (i%2 == 0) {
+			t.Fatalf("G₂ point %d: unexpected result %v", i, ok)
+		}
+	}
+}
+
+func BenchmarkG2IsInSubGroup(b *testing.B) {
+	_, g2, _ := RandomG2(rand.Reader)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g2.IsInSubGroup()
+	}
+}
+
+func TestClearCofactor(t *testing.T) {
+	cofactor := new(big.Int).Lsh(p, 1)
+	cofactor.Sub(cofactor, Order)
+
+	// m = u + 3u·p + u·p² + p³ mod Order.
+	m := new(big.Int).Exp(p, big.NewInt(3), Order)
+	t0 := new(big.Int).Mul(big.NewInt(3), p)
+	t0.Add(t0, new(big.Int).Mul(p, p)).Add(t0, big.NewInt(1)).Mul(t0, u)
+	m.Add(m, t0).Mod(m, Order)
+	if m.Sign() == 0 {
+		t.Fatal("h(ψ) is zero on G₂")
+	}
+
+	// On a point outside of G₂, h(ψ) must agree with the naive scalar
+	// multiplication by the cofactor, up to the factor m/cofactor.
+	ratio := new(big.Int).ModInverse(cofactor, Order)
+	ratio.Mul(ratio, m).Mod(ratio, Order)
+
+	src := &vectorSource{seed: "clear cofactor"}
+	for i := 0; i < 4; i++ {
+		q := &G2{twistPointOutsideG2(t, src)}
+
+		order := &twistPoint{}
+		order.Mul(q.p, new(big.Int).Mul(cofactor, Order))
+		if !order.IsInfinity() {
+			t.Fatal("twist order isn't Order·(2p-Order)")
+		}
+
+		got := new(G2).ClearCofactor(q)
+		if !got.IsInSubGroup() || got.p.IsInfinity() {
+			t.Fatal("result isn't a non-trivial point of G₂")
+		}
+		naive := new(G2).ScalarMult(q, cofactor)
+		if want := naive.ScalarMult(naive, ratio); !got.Equal(want) {
+			t.Fatal("h(ψ)(q) doesn't match the cofactor multiplication")
+		}
+
+		if in := new(G2).Set(q); !in.ClearCofactor(in).Equal(got) {
+			t.Fatal("in-place ClearCofactor doesn't match")
+		}
+	}
+
+	_, g, _ := RandomG2(rand.Reader)
+	if got := new(G2).ClearCofactor(g); !got.Equal(new(G2).ScalarMult(g, m)) {
+		t.Fatal("h(ψ) isn't multiplication by m on G₂")
+	}
+}
+
+// BenchmarkClearCofactor compares ClearCofactor with the multiplication by
+// the cofactor 2p-Order that it replaces.
+func BenchmarkClearCofactor(b *testing.B) {
+	q := twistPointOutsideG2(b, &vectorSource{seed: "clear cofactor"})
+	cofactor := new(big.Int).Lsh(p, 1)
+	cofactor.Sub(cofactor, Order)
+	e := &G2{&twistPoint{}}
+
+	b.Run("ClearCofactor", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.ClearCofactor(&G2{q})
+		}
+	})
+	b.Run("Cofactor", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.p.Mul(q, cofactor)
+		}
+	})
+}
+
+func TestGTIsInSubGroup(t *testing.T) {
+	_, g, _ := RandomGT(rand.Reader)
+	if !g.IsInSubGroup() {
+		t.Fatal("random GT element isn't in GT")
+	}
+	if !(&GT{(&gfP12{}).SetOne()}).IsInSubGroup() {
+		t.Fatal("one isn't in GT")
+	}
+
+	src := &vectorSource{seed: "outside GT"}
+	for name, e := range map[string]*gfP12{
+		"zero":       &gfP12{},
+		"random":     src.nextGFp12(),
+		"cyclotomic": src.nextCyclotomic(),
+		"cofactor":   (&gfP12{}).Exp(src.nextCyclotomic(), Order),
+	} {
+		if (&GT{e}).IsInSubGroup() {
+			t.Errorf("%s element is reported to be in GT", name)
+		}
+		if !e.IsZero() && (&gfP12{}).Exp(e, Order).IsOne() {
+			t.Errorf("%s element has order Order", name)
+		}
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	if _, err := new(G1).UnmarshalStrict(g1.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, g2, _ := RandomG2(rand.Reader)
+	if _, err := new(G2).UnmarshalStrict(g2.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	bad2 := (&G2{twistPointOutsideG2(t, &vectorSource{seed: "strict G2"})}).Marshal()
+	if _, err := new(G2).Unmarshal(bad2); err != nil {
+		t.Fatalf("Unmarshal rejected a point on the twist: %v", err)
+	}
+	if _, err := new(G2).UnmarshalStrict(bad2); err == nil {
+		t.Fatal("UnmarshalStrict accepted a point outside of G₂")
+	}
+
+	_, gt, _ := RandomGT(rand.Reader)
+	if _, err := new(GT).UnmarshalStrict(gt.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	badT := (&GT{(&vectorSource{seed: "strict GT"}).nextCyclotomic()}).Marshal()
+	if _, err := new(GT).Unmarshal(badT); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := new(GT).UnmarshalStrict(badT); err == nil {
+		t.Fatal("UnmarshalStrict accepted an element outside of GT")
+	}
+
+	// A pairing output is accepted and elements outside of the cyclotomic
+	// subgroup, including zero, are rejected.
+	pair := Pair(g1, g2)
+	got := new(GT)
+	if _, err := got.UnmarshalStrict(pair.Marshal()); err != nil || !got.Equal(pair) {
+		t.Fatalf("UnmarshalStrict of a pairing output failed: %v", err)
+	}
+	src := &vectorSource{seed: "strict GT random"}
+	for i, v := range []*gfP12{src.nextGFp12(), {}, Miller(g1, g2).p} {
+		if _, err := new(GT).UnmarshalStrict((&GT{v}).Marshal()); err == nil {
+			t.Fatalf("UnmarshalStrict accepted element %d outside of GT", i)
+		}
+	}
+
+	// e is left unchanged on error.
+	e2 := new(G2).Set(g2)
+	if _, err := e2.UnmarshalStrict(bad2); err == nil || !e2.Equal(g2) {
+		t.Fatal("failed UnmarshalStrict changed the G2 receiver")
+	}
+	eT := new(GT).Set(gt)
+	if _, err := eT.UnmarshalStrict(badT); err == nil || !eT.Equal(gt) {
+		t.Fatal("failed UnmarshalStrict changed the GT receiver")
+	}
+}
+
+func TestIsOnCurve(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	// Sums are left in projective form.
+	a := new(G1).Add(g1, &G1{curveGen})
+	b := new(G2).Add(g2, &G2{twistGen})
+	if a.p.z == *newGFp(1) || b.p.z.IsOne() {
+		t.Fatal("sums are already affine")
+	}
+	before1, before2 := *a.p, *b.p
+	if !a.IsOnCurve() || !b.IsOnCurve() {
+		t.Fatal("projective points aren't on the curve")
+	}
+	if *a.p != before1 || *b.p != before2 {
+		t.Fatal("IsOnCurve modified its receiver")
+	}
+	inf1, inf2 := new(G1).ScalarBaseMult(Order), new(G2).ScalarBaseMult(Order)
+	if !inf1.IsOnCurve() || !inf2.IsOnCurve() {
+		t.Fatal("the point at infinity isn't on the curve")
+	}
+	if new(G1).IsOnCurve() || new(G2).IsOnCurve() {
+		t.Fatal("the zero value is on the curve")
+	}
+
+	// A point of the twist outside of G₂ is on the curve.
+	outside := &G2{twistPointOutsideG2(t, &vectorSource{seed: "IsOnCurve G2"})}
+	if !outside.IsOnCurve() || outside.IsInSubGroup() {
+		t.Fatal("bad point outside of G₂")
+	}
+
+	// Corrupted coordinates.
+	for _, corrupt := range []func(c *curvePoint){
+		func(c *curvePoint) { c.x[0] ^= 1 },
+		func(c *curvePoint) { c.y[3] ^= 1 << 40 },
+		func(c *curvePoint) { c.z[1] ^= 1 },
+		func(c *curvePoint) { c.x = p2 },
+	} {
+		c := &G1{&curvePoint{}}
+		c.p.Set(a.p)
+		corrupt(c.p)
+		if c.IsOnCurve() {
+			t.Fatal("G1.IsOnCurve accepted a corrupted point")
+		}
+	}
+	for _, corrupt := range []func(c *twistPoint){
+		func(c *twistPoint) { c.x.x[0] ^= 1 },
+		func(c *twistPoint) { c.y.y[2] ^= 1 },
+		func(c *twistPoint) { c.z.x[1] ^= 1 << 7 },
+		func(c *twistPoint) { c.y.x = p2 },
+	} {
+		c := &G2{&twistPoint{}}
+		c.p.Set(b.p)
+		corrupt(c.p)
+		if c.IsOnCurve() {
+			t.Fatal("G2.IsOnCurve accepted a corrupted point")
+		}
+	}
+}
+
+func TestGTIsValid(t *testing.T) {
+	_, g, _ := RandomGT(rand.Reader)
+	if !g.IsValid() || !new(GT).ScalarBaseMult(new(big.Int)).IsValid() {
+		t.Fatal("element of GT isn't valid")
+	}
+	if new(GT).IsValid() {
+		t.Fatal("the zero value is valid")
+	}
+	if (&GT{(&vectorSource{seed: "IsValid GT"}).nextCyclotomic()}).IsValid() {
+		t.Fatal("element of the cyclotomic subgroup outside of GT is valid")
+	}
+	if Miller(&G1{curveGen}, &G2{twistGen}).IsValid() {
+		t.Fatal("Miller loop without final exponentiation is valid")
+	}
+
+	c := &GT{(&gfP12{}).Set(g.p)}
+	c.p.y.z.x[0] ^= 1
+	if c.IsValid() {
+		t.Fatal("corrupted element is valid")
+	}
+	c.p.Set(g.p)
+	c.p.x.y.y = p2
+	if c.IsValid() {
+		t.Fatal("element with a coordinate equal to p is valid")
+	}
+}
+
+func BenchmarkGTIsInSubGroup(b *testing.B) {
+	_, g, _ := RandomGT(rand.Reader)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.IsInSubGroup()
+	}
+}
@@ -0,0 +1,28 @@
+// +build bn256debug
+
+package bn256
+
+import (
+	"math/big"
+)
+
+// ScalarMultTrace computes k·p like G1.ScalarMult and returns the value of the
+// accumulator after each step of the double-and-add loop, one step per bit of
+// |k| from the most significant one. Step i holds ⌊|k|/2ⁿ⁻¹⁻ⁱ⌋·p, negated if
+// k is negative, where n is the bit length of |k|, so the last step is the
+// result of ScalarMult. It is meant for comparing against the intermediate
+// values of other implementations and only exists when building with the
+// bn256debug tag.
+func ScalarMultTrace(k *big.Int, p *G1) []*G1 {
+	abs := new(big.Int).Abs(k)
+	steps := make([]*G1, 0, abs.BitLen())
+	new(curvePoint).mul(p.p, abs, func(sum *curvePoint) {
+		step := &G1{&curvePoint{}}
+		step.p.Set(sum)
+		if k.Sign() < 0 {
+			step.p.Neg(step.p)
+		}
+		steps = append(steps, step)
+	})
+	return steps
+}
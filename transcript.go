@@ -0,0 +1,99 @@
+package bn256
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math/big"
+)
+
+// Tags of the items of a Transcript, which keep an item of one type from
+// being read as an item of another.
+const (
+	transcriptDomain byte = iota + 1
+	transcriptG1
+	transcriptG2
+	transcriptGT
+	transcriptScalar
+	transcriptBytes
+	transcriptChallenge
+)
+
+// Transcript accumulates the messages of an interactive proof and derives
+// Fiat-Shamir challenges from them. Every item is absorbed into a running
+// SHA-256 hash as
+//
+//	tag ‖ len(label) ‖ label ‖ len(data) ‖ data
+//
+// where the tag identifies the type of the item, the lengths are 64-bit
+// big-endian numbers and data is the canonical encoding of the item: the
+// output of Marshal for group elements and 32 big-endian bytes for scalars.
+// The framing makes the encoding of a sequence of items unambiguous, so two
+// transcripts only yield the same challenges if they hold the same items
+// with the same labels, in the same order.
+//
+// A Transcript is not safe for concurrent use.
+type Transcript struct {
+	h hash.Hash
+}
+
+// NewTranscript returns a transcript for the protocol named by domain, which
+// separates it from the transcripts of other protocols.
+func NewTranscript(domain string) *Transcript {
+	t := &Transcript{h: sha256.New()}
+	t.absorb(transcriptDomain, domain, nil)
+	return t
+}
+
+func (t *Transcript) absorb(tag byte, label string, data []byte) {
+	var n [8]byte
+	t.h.Write([]byte{tag})
+	binary.BigEndian.PutUint64(n[:], uint64(len(label)))
+	t.h.Write(n[:])
+	t.h.Write([]byte(label))
+	binary.BigEndian.PutUint64(n[:], uint64(len(data)))
+	t.h.Write(n[:])
+	t.h.Write(data)
+}
+
+// Append adds element to the transcript under label. element must be a *G1,
+// *G2, *GT, *big.Int or []byte. Scalars are reduced modulo Order first, so k
+// and k+Order are the same item. Append panics for any other type.
+func (t *Transcript) Append(label string, element interface{}) {
+	switch e := element.(type) {
+	case *G1:
+		t.absorb(transcriptG1, label, e.Marshal())
+	case *G2:
+		t.absorb(transcriptG2, label, e.Marshal())
+	case *GT:
+		t.absorb(transcriptGT, label, e.Marshal())
+	case *big.Int:
+		k := new(big.Int).Mod(e, Order)
+		t.absorb(transcriptScalar, label, k.FillBytes(make([]byte, 32)))
+	case []byte:
+		t.absorb(transcriptBytes, label, e)
+	default:
+		panic("bn256: unsupported transcript element type")
+	}
+}
+
+// Challenge returns a scalar in [0, Order) derived from everything appended
+// to the transcript so far and from label. The challenge is itself absorbed,
+// so consecutive challenges are independent of each other.
+func (t *Transcript) Challenge(label string) *big.Int {
+	t.absorb(transcriptChallenge, label, nil)
+	state := t.h.Sum(nil)
+
+	// Reduce 512 bits modulo Order, so the bias is negligible.
+	var wide []byte
+	for i := byte(0); i < 2; i++ {
+		h := sha256.New()
+		h.Write(state)
+		h.Write([]byte{i})
+		wide = h.Sum(wide)
+	}
+	k := ScalarReduce(wide)
+
+	t.absorb(transcriptChallenge, label, wide)
+	return k
+}
@@ -0,0 +1,75 @@
+package bn256
+
+import (
+	"math/big"
+	"testing"
+)
+
+// transcriptChallengeOf builds a transcript from pairs of labels and elements
+// and returns its first challenge.
+func transcriptChallengeOf(domain string, items ...interface{}) *big.Int {
+	t := NewTranscript(domain)
+	for i := 0; i < len(items); i += 2 {
+		t.Append(items[i].(string), items[i+1])
+	}
+	return t.Challenge("c")
+}
+
+func TestTranscript(t *testing.T) {
+	g1 := &G1{curveGen}
+	g2 := &G2{twistGen}
+	gt := &GT{gfP12Gen}
+	k := big.NewInt(42)
+	items := []interface{}{"g1", g1, "g2", g2, "gt", gt, "k", k, "raw", []byte("raw")}
+
+	c := transcriptChallengeOf("test", items...)
+	if c.Sign() < 0 || c.Cmp(Order) >= 0 {
+		t.Fatalf("challenge %v out of range", c)
+	}
+	if got := transcriptChallengeOf("test", items...); got.Cmp(c) != 0 {
+		t.Fatal("challenges aren't deterministic")
+	}
+
+	// The challenge for this transcript is pinned so that changes to the
+	// encoding are noticed.
+	want := bigFromBase10("38797953116754181384812789028417117026679925272941493039799067199483565912844")
+	if c.Cmp(want) != 0 {
+		t.Fatalf("challenge is %v, want %v", c, want)
+	}
+
+	reduced := append([]interface{}{}, items...)
+	reduced[7] = new(big.Int).Add(k, Order)
+	if got := transcriptChallengeOf("test", reduced...); got.Cmp(c) != 0 {
+		t.Fatal("scalar isn't reduced")
+	}
+
+	different := map[string]*big.Int{
+		"domain":  transcriptChallengeOf("other", items...),
+		"label":   transcriptChallengeOf("test", append([]interface{}{"G1"}, items[1:]...)...),
+		"order":   transcriptChallengeOf("test", append([]interface{}{items[2], items[3], items[0], items[1]}, items[4:]...)...),
+		"missing": transcriptChallengeOf("test", items[2:]...),
+		"scalar":  transcriptChallengeOf("test", append(append([]interface{}{}, items[:7]...), big.NewInt(43), items[8], items[9])...),
+		"framing": transcriptChallengeOf("test", append(append([]interface{}{}, items[:8]...), "ra", []byte("wraw"))...),
+		"type":    transcriptChallengeOf("test", append(append([]interface{}{}, items[:8]...), "raw", new(big.Int).SetBytes([]byte("raw")))...),
+	}
+	for name, got := range different {
+		if got.Cmp(c) == 0 {
+			t.Errorf("changing the %s doesn't change the challenge", name)
+		}
+	}
+
+	tr := NewTranscript("test")
+	c1, c2 := tr.Challenge("c"), tr.Challenge("c")
+	if c1.Cmp(c2) == 0 {
+		t.Error("consecutive challenges are equal")
+	}
+}
+
+func TestTranscriptUnsupportedType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Append didn't panic")
+		}
+	}()
+	NewTranscript("test").Append("bad", 42)
+}
@@ -57,6 +57,17 @@ func (c *twistPoint) IsOnCurve() bool {
 	return *y2 == *x3
 }
 
+// isOnCurveAffineCT returns 1 if (c.x, c.y) satisfies the equation of the
+// twist and 0 otherwise, ignoring c.z and without branching on the
+// coordinates.
+func (c *twistPoint) isOnCurveAffineCT() uint64 {
+	y2, x3 := &gfP2{}, &gfP2{}
+	y2.Square(&c.y)
+	x3.Square(&c.x).Mul(x3, &c.x).Add(x3, twistB)
+
+	return gfp2Equal(y2, x3)
+}
+
 func (c *twistPoint) SetInfinity() {
 	c.x.SetZero()
 	c.y.SetOne()
@@ -68,6 +79,31 @@ func (c *twistPoint) IsInfinity() bool {
 	return c.z.IsZero()
 }
 
+// Equal reports whether c and a represent the same point. For additional
+// comments, see the same function in curve.go.
+func (c *twistPoint) Equal(a *twistPoint) bool {
+	zero := &gfP2{}
+	inf1, inf2 := gfp2Equal(&c.z, zero), gfp2Equal(&a.z, zero)
+
+	z12 := (&gfP2{}).Square(&c.z)
+	z22 := (&gfP2{}).Square(&a.z)
+	u1 := (&gfP2{}).Mul(&c.x, z22)
+	u2 := (&gfP2{}).Mul(&a.x, z12)
+
+	z22.Mul(z22, &a.z)
+	s1 := (&gfP2{}).Mul(&c.y, z22)
+	z12.Mul(z12, &c.z)
+	s2 := (&gfP2{}).Mul(&a.y, z12)
+
+	eq := gfp2Equal(u1, u2) & gfp2Equal(s1, s2) & (1 ^ inf1 ^ inf2)
+	return (eq | inf1&inf2) == 1
+}
+
+// Add sets c to a+b. It handles all exceptional inputs, by branching on them:
+// a point at infinity on either side, a = b (which doubles) and a = -b (which
+// gives infinity). c may alias a or b. The twist has no points of order two,
+// so Double never needs a special case beyond infinity. Add is not constant
+// time.
 func (c *twistPoint) Add(a, b *twistPoint) {
 	// For additional comments, see the same function in curve.go.
 
@@ -191,6 +227,29 @@ func (c *twistPoint) MakeAffine() {
 	c.t.SetOne()
 }
 
+// makeAffineCT converts c to affine form like MakeAffine, but without
+// branching on the coordinates. See the same function for curvePoint.
+func (c *twistPoint) makeAffineCT() uint64 {
+	if c.z.IsOne() {
+		return 0
+	}
+
+	zInv := (&gfP2{}).Invert(&c.z)
+	zero := &gfP2{}
+	infinity := gfp2Equal(zInv, zero)
+
+	t := (&gfP2{}).Mul(&c.y, zInv)
+	zInv2 := (&gfP2{}).Square(zInv)
+	c.y.Mul(t, zInv2)
+	c.x.Mul(&c.x, zInv2)
+
+	one := (&gfP2{}).SetOne()
+	gfp2CMov(&c.y, &c.y, one, infinity)
+	gfp2CMov(&c.z, one, zero, infinity)
+	gfp2CMov(&c.t, one, zero, infinity)
+	return infinity
+}
+
 func (c *twistPoint) Neg(a *twistPoint) {
 	c.x.Set(&a.x)
 	c.y.Neg(&a.y)
@@ -0,0 +1,108 @@
+package bn256
+
+import (
+	"errors"
+)
+
+// untwistedG2Size is the length of an untwisted G₂ point: its two coordinates
+// over GF(p¹²), each in the layout of GT.Marshal.
+const untwistedG2Size = 2 * gtSize
+
+// untwist sets x and y to the coordinates of the point of E(GF(p¹²)) that
+// corresponds to the affine twist point c. With ω⁶ = ξ, the map
+//
+//	(x', y') ↦ (x'·ω², y'·ω³) = (x'·τ, y'·τω)
+//
+// takes y'² = x'³ + 3/ξ to y² = x³ + 3. It's the map miller implicitly uses
+// when it evaluates the lines through twist points at points of G₁.
+func (c *twistPoint) untwist(x, y *gfP12) {
+	x.SetZero()
+	x.y.y.Set(&c.x)
+	y.SetZero()
+	y.x.y.Set(&c.y)
+}
+
+// twist sets c to the twist point corresponding to the point (x, y) of
+// E(GF(p¹²)) and reports whether (x, y) is in the image of untwist, that is
+// whether x is a multiple of τ and y a multiple of τω over GF(p²).
+func (c *twistPoint) twist(x, y *gfP12) bool {
+	xt, yt := &gfP12{}, &gfP12{}
+	xt.y.y.Set(&x.y.y)
+	yt.x.y.Set(&y.x.y)
+	if *xt != *x || *yt != *y {
+		return false
+	}
+	c.x.Set(&x.y.y)
+	c.y.Set(&y.x.y)
+	c.z.SetOne()
+	c.t.SetOne()
+	return true
+}
+
+// MarshalUntwisted converts e into the coordinates (x, y) of the
+// corresponding point on the curve y² = x³ + 3 over GF(p¹²), written as x‖y
+// with each coordinate in the layout of GT.Marshal, for a total of 768 bytes.
+// The point at infinity is encoded as all zeros. This is the representation
+// of G₂ used by implementations that don't work on the sextic twist.
+func (e *G2) MarshalUntwisted() []byte {
+	ret := make([]byte, untwistedG2Size)
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+
+	e.p.MakeAffine()
+	if e.p.IsInfinity() {
+		return ret
+	}
+	x, y := &gfP12{}, &gfP12{}
+	e.p.untwist(x, y)
+	(&GT{x}).marshalTo(ret)
+	(&GT{y}).marshalTo(ret[gtSize:])
+
+	if debug {
+		checkRoundTripG2Untwisted(e, ret)
+	}
+	return ret
+}
+
+// UnmarshalUntwisted sets e to the point encoded in m by MarshalUntwisted and
+// returns the rest of m. It returns an error if the coordinates are not less
+// than p, the point is not the image of a twist point or is not on the curve.
+func (e *G2) UnmarshalUntwisted(m []byte) ([]byte, error) {
+	if len(m) < untwistedG2Size {
+		return nil, errors.New("bn256: not enough data")
+	}
+	for i := 0; i < untwistedG2Size; i += 32 {
+		if !isLikelyCoordinate(m[i:]) {
+			return nil, errors.New("bn256: coordinate not less than p")
+		}
+	}
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	if isZeroBytes(m[:untwistedG2Size]) {
+		e.p.SetInfinity()
+		return m[untwistedG2Size:], nil
+	}
+
+	x, y := &GT{}, &GT{}
+	x.Unmarshal(m)
+	y.Unmarshal(m[gtSize:])
+	c := &twistPoint{}
+	if !c.twist(x.p, y.p) || !c.IsOnCurve() {
+		return nil, errors.New("bn256: malformed point")
+	}
+	e.p.Set(c)
+	return m[untwistedG2Size:], nil
+}
+
+// PairUntwisted returns e(g1, q), where q is given in the untwisted encoding
+// of MarshalUntwisted. The point is mapped to the twist before the pairing is
+// computed, so the result is the same as Pair's for the twisted point.
+func PairUntwisted(g1 *G1, q []byte) (*GT, error) {
+	g2 := new(G2)
+	if _, err := g2.UnmarshalUntwisted(q); err != nil {
+		return nil, err
+	}
+	return Pair(g1, g2), nil
+}
@@ -0,0 +1,117 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// onUntwistedCurve reports whether y² = x³ + 3 over GF(p¹²).
+func onUntwistedCurve(x, y *gfP12) bool {
+	three := &gfP12{}
+	three.y.z.y = *newGFp(3)
+	lhs := (&gfP12{}).Square(y)
+	rhs := (&gfP12{}).Square(x)
+	rhs.Mul(rhs, x).Add(rhs, three)
+	return *lhs == *rhs
+}
+
+func TestUntwist(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		_, q, _ := RandomG2(rand.Reader)
+		q.p.MakeAffine()
+
+		x, y := &gfP12{}, &gfP12{}
+		q.p.untwist(x, y)
+		if !onUntwistedCurve(x, y) {
+			t.Fatal("untwisted point isn't on the curve")
+		}
+
+		// ψ is the Frobenius map on the untwisted curve.
+		psi := &twistPoint{}
+		psi.psi(q.p)
+		psi.MakeAffine()
+		px, py := &gfP12{}, &gfP12{}
+		psi.untwist(px, py)
+		if *(&gfP12{}).Frobenius(x) != *px || *(&gfP12{}).Frobenius(y) != *py {
+			t.Fatal("untwist doesn't map ψ to the Frobenius map")
+		}
+
+		// Adding the untwisted points with the affine formulas over
+		// GF(p¹²) agrees with adding the twist points.
+		_, r, _ := RandomG2(rand.Reader)
+		r.p.MakeAffine()
+		sum := new(G2).Add(q, r)
+		sum.p.MakeAffine()
+		qx, qy, rx, ry, sx, sy := &gfP12{}, &gfP12{}, &gfP12{}, &gfP12{}, &gfP12{}, &gfP12{}
+		q.p.untwist(qx, qy)
+		r.p.untwist(rx, ry)
+		sum.p.untwist(sx, sy)
+		l := (&gfP12{}).Sub(ry, qy)
+		l.Mul(l, (&gfP12{}).Invert((&gfP12{}).Sub(rx, qx)))
+		x3 := (&gfP12{}).Square(l)
+		x3.Sub(x3, qx).Sub(x3, rx)
+		y3 := (&gfP12{}).Sub(qx, x3)
+		y3.Mul(y3, l).Sub(y3, qy)
+		if *x3 != *sx || *y3 != *sy {
+			t.Fatal("untwist isn't a group homomorphism")
+		}
+
+		back := &twistPoint{}
+		if !back.twist(x, y) || !back.Equal(q.p) {
+			t.Fatal("twist(untwist(q)) != q")
+		}
+	}
+}
+
+func TestG2Untwisted(t *testing.T) {
+	_, p, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG2(rand.Reader)
+
+	m := q.MarshalUntwisted()
+	if len(m) != untwistedG2Size {
+		t.Fatalf("encoding is %d bytes long", len(m))
+	}
+	got := new(G2)
+	rest, err := got.UnmarshalUntwisted(append(m, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, []byte{1}) || !got.Equal(q) {
+		t.Fatal("round trip doesn't match")
+	}
+
+	e, err := PairUntwisted(p, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Equal(Pair(p, q)) {
+		t.Fatal("PairUntwisted doesn't match Pair")
+	}
+
+	inf := new(G2).ScalarBaseMult(new(big.Int)).MarshalUntwisted()
+	if !isZeroBytes(inf) {
+		t.Fatal("point at infinity isn't all zeros")
+	}
+	if _, err := got.UnmarshalUntwisted(inf); err != nil || !got.p.IsInfinity() {
+		t.Fatal("point at infinity wasn't decoded")
+	}
+
+	notImage := append([]byte{}, m...)
+	notImage[gtSize-1] ^= 1
+	notOnCurve := append([]byte{}, m...)
+	notOnCurve[gtSize+4*32-1] ^= 1 // the real part of y'
+	notReduced := append([]byte{}, m...)
+	copy(notReduced, pBytes)
+	for name, m := range map[string][]byte{
+		"short":        m[:len(m)-1],
+		"not an image": notImage,
+		"not on curve": notOnCurve,
+		"not reduced":  notReduced,
+	} {
+		if _, err := new(G2).UnmarshalUntwisted(m); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package bn256
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the files in testdata")
+
+const fieldVectorsFile = "field_vectors.txt"
+
+// vectorSource deterministically derives field elements from a seed, so that
+// the vectors can be regenerated by other implementations.
+type vectorSource struct {
+	seed    string
+	counter uint32
+}
+
+// next returns the next element of GF(p): SHA-256(seed‖counter‖0) and
+// SHA-256(seed‖counter‖1), concatenated and reduced mod p.
+func (s *vectorSource) next() *gfP {
+	var buf []byte
+	for i := byte(0); i < 2; i++ {
+		h := sha256.New()
+		h.Write([]byte(s.seed))
+		binary.Write(h, binary.BigEndian, s.counter)
+		h.Write([]byte{i})
+		buf = h.Sum(buf)
+	}
+	s.counter++
+	return togfP(new(big.Int).Mod(new(big.Int).SetBytes(buf), p))
+}
+
+func (s *vectorSource) nextGFp2() *gfP2 {
+	return &gfP2{*s.next(), *s.next()}
+}
+
+func (s *vectorSource) nextGFp6() *gfP6 {
+	return &gfP6{*s.nextGFp2(), *s.nextGFp2(), *s.nextGFp2()}
+}
+
+func (s *vectorSource) nextGFp12() *gfP12 {
+	return &gfP12{*s.nextGFp6(), *s.nextGFp6()}
+}
+
+// nextCyclotomic returns an element of the cyclotomic subgroup, obtained by
+// raising a random element to (p⁶-1)(p²+1).
+func (s *vectorSource) nextCyclotomic() *gfP12 {
+	a := s.nextGFp12()
+	t := (&gfP12{}).Invert(a)
+	a.Conjugate(a).Mul(a, t)
+	t.FrobeniusP2(a)
+	return a.Mul(a, t)
+}
+
+// hexCoordinates encodes the canonical (non-Montgomery) coordinates of a
+// field element, most significant coordinate first and comma separated.
+func hexCoordinates(in ...*gfP) string {
+	parts := make([]string, len(in))
+	buf, t := make([]byte, 32), &gfP{}
+	for i, e := range in {
+		montDecode(t, e)
+		t.Marshal(buf)
+		parts[i] = hex.EncodeToString(buf)
+	}
+	return strings.Join(parts, ",")
+}
+
+func hexGFp2(e *gfP2) string { return hexCoordinates(&e.x, &e.y) }
+
+func hexGFp6(e *gfP6) string {
+	return strings.Join([]string{hexGFp2(&e.x), hexGFp2(&e.y), hexGFp2(&e.z)}, ",")
+}
+
+func hexGFp12(e *gfP12) string { return hexGFp6(&e.x) + "," + hexGFp6(&e.y) }
+
+// fieldVectors runs a fixed sequence of field operations on seeded inputs.
+// Every line has the form "op input... = output".
+func fieldVectors() []string {
+	var out []string
+	emit := func(op string, values ...string) {
+		n := len(values) - 1
+		out = append(out, op+" "+strings.Join(values[:n], " ")+" = "+values[n])
+	}
+
+	const n = 4
+	src := &vectorSource{seed: "bn256 field vectors"}
+	for i := 0; i < n; i++ {
+		a, b, c := src.next(), src.next(), &gfP{}
+		gfpAdd(c, a, b)
+		emit("gfp.add", hexCoordinates(a), hexCoordinates(b), hexCoordinates(c))
+		gfpSub(c, a, b)
+		emit("gfp.sub", hexCoordinates(a), hexCoordinates(b), hexCoordinates(c))
+		gfpMul(c, a, b)
+		emit("gfp.mul", hexCoordinates(a), hexCoordinates(b), hexCoordinates(c))
+		gfpMul(c, a, a)
+		emit("gfp.square", hexCoordinates(a), hexCoordinates(c))
+		gfpNeg(c, a)
+		emit("gfp.neg", hexCoordinates(a), hexCoordinates(c))
+		c.Invert(a)
+		emit("gfp.invert", hexCoordinates(a), hexCoordinates(c))
+		gfpMul(b, a, a)
+		c.Sqrt(b)
+		emit("gfp.sqrt", hexCoordinates(b), hexCoordinates(c))
+	}
+
+	for i := 0; i < n; i++ {
+		a, b, c := src.nextGFp2(), src.nextGFp2(), &gfP2{}
+		emit("gfp2.add", hexGFp2(a), hexGFp2(b), hexGFp2(c.Add(a, b)))
+		emit("gfp2.sub", hexGFp2(a), hexGFp2(b), hexGFp2(c.Sub(a, b)))
+		emit("gfp2.mul", hexGFp2(a), hexGFp2(b), hexGFp2(c.Mul(a, b)))
+		emit("gfp2.square", hexGFp2(a), hexGFp2(c.Square(a)))
+		emit("gfp2.mulxi", hexGFp2(a), hexGFp2(c.MulXi(a)))
+		emit("gfp2.conjugate", hexGFp2(a), hexGFp2(c.Conjugate(a)))
+		emit("gfp2.invert", hexGFp2(a), hexGFp2(c.Invert(a)))
+	}
+
+	for i := 0; i < n/2; i++ {
+		a, b, c := src.nextGFp6(), src.nextGFp6(), &gfP6{}
+		emit("gfp6.add", hexGFp6(a), hexGFp6(b), hexGFp6(c.Add(a, b)))
+		emit("gfp6.sub", hexGFp6(a), hexGFp6(b), hexGFp6(c.Sub(a, b)))
+		emit("gfp6.mul", hexGFp6(a), hexGFp6(b), hexGFp6(c.Mul(a, b)))
+		emit("gfp6.square", hexGFp6(a), hexGFp6(c.Square(a)))
+		emit("gfp6.multau", hexGFp6(a), hexGFp6(c.MulTau(a)))
+		emit("gfp6.invert", hexGFp6(a), hexGFp6(c.Invert(a)))
+		emit("gfp6.frobenius", hexGFp6(a), hexGFp6(c.Frobenius(a)))
+		emit("gfp6.frobeniusp2", hexGFp6(a), hexGFp6(c.FrobeniusP2(a)))
+		emit("gfp6.frobeniusp4", hexGFp6(a), hexGFp6(c.FrobeniusP4(a)))
+	}
+
+	for i := 0; i < n/2; i++ {
+		a, b, c := src.nextGFp12(), src.nextGFp12(), &gfP12{}
+		emit("gfp12.mul", hexGFp12(a), hexGFp12(b), hexGFp12(c.Mul(a, b)))
+		emit("gfp12.square", hexGFp12(a), hexGFp12(c.Square(a)))
+		emit("gfp12.invert", hexGFp12(a), hexGFp12(c.Invert(a)))
+		emit("gfp12.conjugate", hexGFp12(a), hexGFp12(c.Conjugate(a)))
+		emit("gfp12.frobenius", hexGFp12(a), hexGFp12(c.Frobenius(a)))
+		emit("gfp12.frobeniusp2", hexGFp12(a), hexGFp12(c.FrobeniusP2(a)))
+		emit("gfp12.frobeniusp4", hexGFp12(a), hexGFp12(c.FrobeniusP4(a)))
+
+		cyclo := src.nextCyclotomic()
+		emit("gfp12.squarecyclo6", hexGFp12(cyclo), hexGFp12(c.SquareCyclo6(cyclo)))
+	}
+
+	return out
+}
+
+func TestFieldVectors(t *testing.T) {
+	checkGolden(t, filepath.Join("testdata", fieldVectorsFile), fieldVectors())
+}
+
+// checkGolden compares got line by line with the golden file at path, or
+// rewrites the file when the test runs with -update.
+func checkGolden(t *testing.T, path string, got []string) {
+	t.Helper()
+	if *update {
+		data := strings.Join(got, "\n") + "\n"
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var want []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		want = append(want, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			name := strings.SplitN(want[i], " ", 2)[0]
+			t.Errorf("line %d (%s) doesn't match:\ngot:  %s\nwant: %s", i+1, name, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+)
+
+// VRFDST is the domain separation tag with which VRF inputs are hashed to G₁.
+// It differs from BLSSignatureDST, so that VRF proofs can't be used as BLS
+// signatures and vice versa.
+const VRFDST = "BN256-VRF-G1"
+
+// vrfOutput returns the VRF output for proof, SHA-256 of its encoding.
+func vrfOutput(proof *G1) []byte {
+	h := sha256.Sum256(proof.Marshal())
+	return h[:]
+}
+
+// VRFProve evaluates the verifiable random function for the secret key sk,
+// whose public key is sk·g₂ in G₂, at alpha. The proof is sk·H(alpha), with H
+// being HashG1 under VRFDST, and the 32-byte output beta is SHA-256 of the
+// encoded proof. Both are uniquely determined by sk and alpha.
+func VRFProve(sk *big.Int, alpha []byte) (beta []byte, proof *G1) {
+	proof = HashG1(alpha, []byte(VRFDST))
+	proof.ScalarMult(proof, sk)
+	return vrfOutput(proof), proof
+}
+
+// VRFVerify reports whether proof is a valid VRF proof for alpha under pk and
+// beta is the output it determines, that is whether
+//
+//	e(proof, g₂) = e(H(alpha), pk)
+//
+// It returns false if pk or proof is the point at infinity. The caller must
+// make sure that pk is in G₂, for example by decoding it with Unmarshal.
+func VRFVerify(pk *G2, alpha, beta []byte, proof *G1) bool {
+	if pk.p.IsInfinity() || proof.p.IsInfinity() {
+		return false
+	}
+	if !bytes.Equal(beta, vrfOutput(proof)) {
+		return false
+	}
+
+	negProof := &curvePoint{}
+	negProof.Neg(proof.p)
+	h := HashG1(alpha, []byte(VRFDST))
+	return pairingCheck([]*curvePoint{negProof, h.p}, []*twistPoint{twistGen, pk.p})
+}
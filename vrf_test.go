@@ -0,0 +1,58 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestVRF(t *testing.T) {
+	sk, pk, _ := RandomG2(rand.Reader)
+	alpha := []byte("input")
+
+	beta, proof := VRFProve(sk, alpha)
+	if len(beta) != 32 {
+		t.Fatalf("output is %d bytes long", len(beta))
+	}
+	if !VRFVerify(pk, alpha, beta, proof) {
+		t.Fatal("valid proof was rejected")
+	}
+
+	beta2, proof2 := VRFProve(sk, alpha)
+	if !bytes.Equal(beta, beta2) || !proof.Equal(proof2) {
+		t.Fatal("VRF isn't deterministic")
+	}
+
+	if VRFVerify(pk, []byte("other input"), beta, proof) {
+		t.Fatal("proof was accepted for a different input")
+	}
+	_, otherPK, _ := RandomG2(rand.Reader)
+	if VRFVerify(otherPK, alpha, beta, proof) {
+		t.Fatal("proof was accepted under a different key")
+	}
+
+	badBeta := append([]byte(nil), beta...)
+	badBeta[0] ^= 1
+	if VRFVerify(pk, alpha, badBeta, proof) {
+		t.Fatal("wrong output was accepted")
+	}
+
+	badProof := new(G1).Add(proof, &G1{curveGen})
+	if VRFVerify(pk, alpha, vrfOutput(badProof), badProof) {
+		t.Fatal("wrong proof was accepted")
+	}
+
+	// A BLS signature of alpha under the same key is not a VRF proof.
+	sig := HashG1(alpha, []byte(BLSSignatureDST))
+	sig.ScalarMult(sig, sk)
+	if VRFVerify(pk, alpha, vrfOutput(sig), sig) {
+		t.Fatal("BLS signature was accepted as a proof")
+	}
+
+	inf := new(G1).ScalarBaseMult(new(big.Int))
+	infPK := new(G2).ScalarBaseMult(new(big.Int))
+	if VRFVerify(pk, alpha, vrfOutput(inf), inf) || VRFVerify(infPK, alpha, vrfOutput(inf), inf) {
+		t.Fatal("point at infinity was accepted")
+	}
+}